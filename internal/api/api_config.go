@@ -1,28 +1,84 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
+	"github.com/lib/pq"
 	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/jobs"
 )
 
 type ApiConfig struct {
 	DATABASE *database.Queries
+	DB       *sql.DB
+	// BaseUrl is the public URL of the API, without trailing slash, from
+	// which the links of the output feeds are built.
+	BaseUrl        string
+	filterRuleRuns *jobs.Queue[database.FilterRuleRun]
+	opmlImports    *jobs.Queue[database.OpmlImport]
 }
 
-func NewApiConfig(conn *sql.DB) *ApiConfig {
-	return &ApiConfig{
+func NewApiConfig(conn *sql.DB, baseUrl string) *ApiConfig {
+	apiCfg := &ApiConfig{
 		DATABASE: database.New(conn),
+		DB:       conn,
+		BaseUrl:  baseUrl,
 	}
+	apiCfg.filterRuleRuns = &jobs.Queue[database.FilterRuleRun]{
+		Name:    "filter rule run",
+		Workers: 2,
+		Requeue: apiCfg.DATABASE.RequeueFilterRuleRuns,
+		Claim:   apiCfg.DATABASE.ClaimFilterRuleRun,
+		Run:     apiCfg.runFilterRule,
+		Fail:    apiCfg.failFilterRuleRun,
+	}
+	apiCfg.opmlImports = &jobs.Queue[database.OpmlImport]{
+		Name:    "OPML import",
+		Workers: 2,
+		Requeue: apiCfg.DATABASE.RequeueOpmlImports,
+		Claim:   apiCfg.DATABASE.ClaimOpmlImport,
+		Run:     apiCfg.runOpmlImport,
+		Fail:    apiCfg.failOpmlImport,
+	}
+	return apiCfg
+}
+
+// StartJobs starts the workers of the background jobs.
+func (apiCfg *ApiConfig) StartJobs() {
+	apiCfg.filterRuleRuns.Start()
+	apiCfg.opmlImports.Start()
+}
+
+// withTx runs fn with queries bound to a transaction, committed when fn
+// succeeds and rolled back otherwise.
+func (apiCfg *ApiConfig) withTx(ctx context.Context, fn func(queries *database.Queries) error) error {
+	tx, err := apiCfg.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(apiCfg.DATABASE.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isUniqueViolation reports whether err is the violation of the unique
+// constraint with the given name.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
 }
 
 func (apiCfg *ApiConfig) SetupRouter() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: false,
@@ -35,15 +91,73 @@ func (apiCfg *ApiConfig) SetupRouter() *chi.Mux {
 	// Users endpoints
 	v1Router.Post("/users", apiCfg.HandleCreateUser)
 	v1Router.Get("/users", apiCfg.MiddlewareAuth(apiCfg.HandleGetUser))
+	v1Router.Get("/users/{userID}/feeds/{file}", apiCfg.HandleGetOutputFeed)
+	// Feed tokens endpoints
+	v1Router.Post("/feed-tokens", apiCfg.MiddlewareAuth(apiCfg.HandleCreateFeedToken))
+	v1Router.Get("/feed-tokens", apiCfg.MiddlewareAuth(apiCfg.HandleGetFeedTokens))
+	v1Router.Delete("/feed-tokens/{feedTokenID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteFeedToken))
 	// Feeds endpoints
 	v1Router.Post("/feeds", apiCfg.MiddlewareAuth(apiCfg.HandleCreateFeed))
 	v1Router.Get("/feeds", apiCfg.HandleGetAllFeeds)
+	v1Router.Get("/feeds/{feedID}", apiCfg.HandleGetFeed)
+	v1Router.Patch("/feeds/{feedID}", apiCfg.MiddlewareAuth(apiCfg.HandleUpdateFeed))
+	v1Router.Delete("/feeds/{feedID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteFeed))
+	v1Router.Get("/feeds/{feedID}/icon", apiCfg.HandleGetFeedIcon)
+	v1Router.Get("/feeds/{feedID}/posts", apiCfg.HandleGetFeedPosts)
 	// Feed follows endpoints
 	v1Router.Post("/feeds/follows", apiCfg.MiddlewareAuth(apiCfg.HandleCreateFeedFollow))
 	v1Router.Get("/feeds/follows", apiCfg.MiddlewareAuth(apiCfg.HandleGetFeedsFollowedByUser))
+	v1Router.Get("/feeds/follows/unread-counts", apiCfg.MiddlewareAuth(apiCfg.HandleGetUnreadCounts))
+	v1Router.Patch("/feeds/follows/{feedFollowID}", apiCfg.MiddlewareAuth(apiCfg.HandleUpdateFeedFollow))
 	v1Router.Delete("/feeds/follows/{feedFollowID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteFeedFollow))
+	v1Router.Put("/feeds/follows/{feedFollowID}/category", apiCfg.MiddlewareAuth(apiCfg.HandleSetFeedFollowCategory))
+	// Subscriptions endpoints
+	v1Router.Post("/subscriptions", apiCfg.MiddlewareAuth(apiCfg.HandleCreateSubscription))
+	// OPML endpoints
+	v1Router.Post("/opml/import", apiCfg.MiddlewareAuth(apiCfg.HandleImportOpml))
+	v1Router.Get("/opml/import/{jobID}", apiCfg.MiddlewareAuth(apiCfg.HandleGetOpmlImport))
+	v1Router.Get("/opml/export", apiCfg.MiddlewareAuth(apiCfg.HandleExportOpml))
+	// Categories endpoints
+	v1Router.Post("/categories", apiCfg.MiddlewareAuth(apiCfg.HandleCreateCategory))
+	v1Router.Get("/categories", apiCfg.MiddlewareAuth(apiCfg.HandleGetCategories))
+	v1Router.Put("/categories/{categoryID}", apiCfg.MiddlewareAuth(apiCfg.HandleUpdateCategory))
+	v1Router.Delete("/categories/{categoryID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteCategory))
+	// Collections endpoints
+	v1Router.Post("/collections", apiCfg.MiddlewareAuth(apiCfg.HandleCreateCollection))
+	v1Router.Get("/collections", apiCfg.MiddlewareAuth(apiCfg.HandleGetCollections))
+	v1Router.Get("/collections/{collectionID}", apiCfg.HandleGetCollection)
+	v1Router.Put("/collections/{collectionID}", apiCfg.MiddlewareAuth(apiCfg.HandleUpdateCollection))
+	v1Router.Delete("/collections/{collectionID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteCollection))
+	v1Router.Get("/collections/{collectionID}/posts", apiCfg.HandleGetCollectionPosts)
+	v1Router.Put("/collections/{collectionID}/posts/{postID}", apiCfg.MiddlewareAuth(apiCfg.HandleAddCollectionPost))
+	v1Router.Delete("/collections/{collectionID}/posts/{postID}", apiCfg.MiddlewareAuth(apiCfg.HandleRemoveCollectionPost))
+	v1Router.Get("/collections/{collectionID}/page", apiCfg.HandleGetCollectionPage)
+	v1Router.Get("/collections/{collectionID}/rss", apiCfg.HandleGetCollectionRSS)
+	// Filter rules endpoints
+	v1Router.Post("/filters", apiCfg.MiddlewareAuth(apiCfg.HandleCreateFilterRule))
+	v1Router.Get("/filters", apiCfg.MiddlewareAuth(apiCfg.HandleGetFilterRules))
+	v1Router.Put("/filters/{filterRuleID}", apiCfg.MiddlewareAuth(apiCfg.HandleUpdateFilterRule))
+	v1Router.Delete("/filters/{filterRuleID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteFilterRule))
+	v1Router.Post("/filters/{filterRuleID}/apply", apiCfg.MiddlewareAuth(apiCfg.HandleApplyFilterRule))
+	v1Router.Get("/filters/{filterRuleID}/runs/{runID}", apiCfg.MiddlewareAuth(apiCfg.HandleGetFilterRuleRun))
+	// Mutes endpoints
+	v1Router.Post("/mutes", apiCfg.MiddlewareAuth(apiCfg.HandleCreateMute))
+	v1Router.Get("/mutes", apiCfg.MiddlewareAuth(apiCfg.HandleGetMutes))
+	v1Router.Delete("/mutes/{muteID}", apiCfg.MiddlewareAuth(apiCfg.HandleDeleteMute))
+	// Tags endpoints
+	v1Router.Get("/tags", apiCfg.MiddlewareAuth(apiCfg.HandleGetTags))
 	// Posts endpoints
 	v1Router.Get("/posts", apiCfg.MiddlewareAuth(apiCfg.HandleGetPostsForUser))
+	v1Router.Get("/posts/search", apiCfg.MiddlewareAuth(apiCfg.HandleSearchPosts))
+	v1Router.Get("/posts/starred", apiCfg.MiddlewareAuth(apiCfg.HandleGetStarredPosts))
+	v1Router.Put("/posts/{postID}/star", apiCfg.MiddlewareAuth(apiCfg.HandleStarPost))
+	v1Router.Delete("/posts/{postID}/star", apiCfg.MiddlewareAuth(apiCfg.HandleUnstarPost))
+	v1Router.Put("/posts/{postID}/hide", apiCfg.MiddlewareAuth(apiCfg.HandleHidePost))
+	v1Router.Delete("/posts/{postID}/hide", apiCfg.MiddlewareAuth(apiCfg.HandleUnhidePost))
+	v1Router.Put("/posts/{postID}/tags", apiCfg.MiddlewareAuth(apiCfg.HandleSetPostTags))
+	v1Router.Post("/posts/mark-read", apiCfg.MiddlewareAuth(apiCfg.HandleMarkPostsAsRead))
+	v1Router.Post("/posts/{postID}/read", apiCfg.MiddlewareAuth(apiCfg.HandleMarkPostAsRead))
+	v1Router.Delete("/posts/{postID}/read", apiCfg.MiddlewareAuth(apiCfg.HandleMarkPostAsUnread))
 	router.Mount("/v1", v1Router)
 	return router
 }
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/dbtest"
+)
+
+const testBaseUrl = "https://reader.example.com"
+
+// newTestApiConfig returns an ApiConfig on a migrated test database, without
+// its background jobs started.
+func newTestApiConfig(t *testing.T) *ApiConfig {
+	t.Helper()
+	return NewApiConfig(dbtest.Migrate(t), testBaseUrl)
+}
+
+func createTestUser(t *testing.T, apiCfg *ApiConfig, name string) database.User {
+	t.Helper()
+	user, err := apiCfg.DATABASE.CreateUser(context.Background(), database.CreateUserParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      name,
+		ApiKey:    uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("Error creating user: %v", err)
+	}
+	return user
+}
+
+// createTestFeed creates a feed added by user, who follows it.
+func createTestFeed(t *testing.T, apiCfg *ApiConfig, user database.User, url string) database.Feed {
+	t.Helper()
+	feed, err := apiCfg.DATABASE.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		Name:         url,
+		Url:          url,
+		UserID:       user.ID,
+		CanonicalUrl: url,
+	})
+	if err != nil {
+		t.Fatalf("Error creating feed: %v", err)
+	}
+	followTestFeed(t, apiCfg, user, feed)
+	return feed
+}
+
+func followTestFeed(t *testing.T, apiCfg *ApiConfig, user database.User, feed database.Feed) database.FeedFollow {
+	t.Helper()
+	feedFollow, err := apiCfg.DATABASE.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		FeedID:    feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("Error following feed: %v", err)
+	}
+	return feedFollow
+}
+
+func createTestPost(t *testing.T, apiCfg *ApiConfig, feed database.Feed, title string) database.Post {
+	t.Helper()
+	id := uuid.New()
+	url := feed.Url + "/" + id.String()
+	post, err := apiCfg.DATABASE.CreatePost(context.Background(), database.CreatePostParams{
+		ID:           id,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		Title:        title,
+		Url:          url,
+		Description:  sql.NullString{String: title, Valid: true},
+		PublishedAt:  time.Now().UTC(),
+		FeedID:       feed.ID,
+		CanonicalUrl: url,
+	})
+	if err != nil {
+		t.Fatalf("Error creating post: %v", err)
+	}
+	return post
+}
+
+// newTestRequest returns a request carrying the route parameters chi would
+// have matched, given as name and value pairs.
+func newTestRequest(method string, target string, body string, urlParams ...string) *http.Request {
+	var r *http.Request
+	if body == "" {
+		r = httptest.NewRequest(method, target, nil)
+	} else {
+		r = httptest.NewRequest(method, target, strings.NewReader(body))
+	}
+	routeContext := chi.NewRouteContext()
+	for i := 0; i+1 < len(urlParams); i += 2 {
+		routeContext.URLParams.Add(urlParams[i], urlParams[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, routeContext))
+}
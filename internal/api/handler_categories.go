@@ -0,0 +1,90 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleCreateCategory(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateCategoryParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	category, err := apiCfg.DATABASE.CreateCategory(r.Context(), database.CreateCategoryParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		Name:      params.Name,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating category: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewCategoryFromDatabase(category))
+}
+
+func (apiCfg *ApiConfig) HandleGetCategories(w http.ResponseWriter, r *http.Request, user database.User) {
+	categories, err := apiCfg.DATABASE.GetCategoriesForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting categories: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewCategoriesFromDatabase(categories))
+}
+
+func (apiCfg *ApiConfig) HandleUpdateCategory(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.UpdateCategoryParams{}
+	if err := params.Decode(chi.URLParam(r, "categoryID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	category, err := apiCfg.DATABASE.UpdateCategory(r.Context(), database.UpdateCategoryParams{
+		ID:     params.ID,
+		UserID: user.ID,
+		Name:   params.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Category not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error updating category: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewCategoryFromDatabase(category))
+}
+
+func (apiCfg *ApiConfig) HandleDeleteCategory(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.DeleteCategoryParams{}
+	if err := params.Decode(chi.URLParam(r, "categoryID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.DeleteCategory(r.Context(), database.DeleteCategoryParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting category: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+	"github.com/mellomaths/rss-aggregator/internal/pages"
+	"github.com/mellomaths/rss-aggregator/internal/syndication"
+)
+
+// publicCollectionSize is the number of posts shown by the public page and
+// the RSS feed of a collection.
+const publicCollectionSize = 100
+
+func (apiCfg *ApiConfig) HandleCreateCollection(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateCollectionParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	collection, err := apiCfg.DATABASE.CreateCollection(r.Context(), database.CreateCollectionParams{
+		ID:          uuid.New(),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+		UserID:      user.ID,
+		Title:       params.Title,
+		Description: params.NullDescription(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating collection: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewCollectionFromDatabase(collection))
+}
+
+func (apiCfg *ApiConfig) HandleGetCollections(w http.ResponseWriter, r *http.Request, user database.User) {
+	collections, err := apiCfg.DATABASE.GetCollectionsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting collections: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewCollectionsFromDatabase(collections))
+}
+
+func (apiCfg *ApiConfig) HandleUpdateCollection(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.UpdateCollectionParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	collection, err := apiCfg.DATABASE.UpdateCollection(r.Context(), database.UpdateCollectionParams{
+		ID:          params.ID,
+		UserID:      user.ID,
+		Title:       params.Title,
+		Description: params.NullDescription(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Collection not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error updating collection: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewCollectionFromDatabase(collection))
+}
+
+func (apiCfg *ApiConfig) HandleDeleteCollection(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CollectionIDParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.DeleteCollection(r.Context(), database.DeleteCollectionParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting collection: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleAddCollectionPost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CollectionPostParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID"), chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	if _, ok := apiCfg.getOwnedCollection(w, r, user, params.CollectionID); !ok {
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPost(r.Context(), params.PostID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.AddPostToCollection(r.Context(), database.AddPostToCollectionParams{
+		CollectionID: params.CollectionID,
+		PostID:       params.PostID,
+		AddedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error adding post to collection: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleRemoveCollectionPost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CollectionPostParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID"), chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	if _, ok := apiCfg.getOwnedCollection(w, r, user, params.CollectionID); !ok {
+		return
+	}
+	err := apiCfg.DATABASE.RemovePostFromCollection(r.Context(), database.RemovePostFromCollectionParams{
+		CollectionID: params.CollectionID,
+		PostID:       params.PostID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error removing post from collection: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+// getOwnedCollection returns the collection when it belongs to the user, and
+// responds 404 otherwise.
+func (apiCfg *ApiConfig) getOwnedCollection(w http.ResponseWriter, r *http.Request, user database.User, collectionID uuid.UUID) (database.Collection, bool) {
+	collection, ok := apiCfg.getCollection(w, r, collectionID)
+	if !ok {
+		return collection, false
+	}
+	if collection.UserID != user.ID {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Collection not found")
+		return collection, false
+	}
+	return collection, true
+}
+
+func (apiCfg *ApiConfig) getCollection(w http.ResponseWriter, r *http.Request, collectionID uuid.UUID) (database.Collection, bool) {
+	collection, err := apiCfg.DATABASE.GetCollection(r.Context(), collectionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Collection not found")
+		return collection, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting collection: %v", err))
+		return collection, false
+	}
+	return collection, true
+}
+
+// HandleGetCollection returns any collection, collections being public.
+func (apiCfg *ApiConfig) HandleGetCollection(w http.ResponseWriter, r *http.Request) {
+	params := models.CollectionIDParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	collection, ok := apiCfg.getCollection(w, r, params.ID)
+	if !ok {
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewCollectionFromDatabase(collection))
+}
+
+func (apiCfg *ApiConfig) HandleGetCollectionPosts(w http.ResponseWriter, r *http.Request) {
+	params := models.CollectionIDParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	pagination := models.PaginatedParams{}
+	if err := pagination.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	if err := pagination.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	if _, ok := apiCfg.getCollection(w, r, params.ID); !ok {
+		return
+	}
+	posts, err := apiCfg.DATABASE.GetCollectionPosts(r.Context(), database.GetCollectionPostsParams{
+		CollectionID: params.ID,
+		Limit:        pagination.Limit,
+		Offset:       pagination.Offset,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	total, err := apiCfg.DATABASE.CountCollectionPosts(r.Context(), params.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting posts: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), pagination.Offset, pagination.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewPostsFromDatabase(posts), total, pagination))
+}
+
+// HandleGetCollectionPage serves the public HTML page of a collection.
+func (apiCfg *ApiConfig) HandleGetCollectionPage(w http.ResponseWriter, r *http.Request) {
+	collection, owner, posts, ok := apiCfg.getPublicCollection(w, r)
+	if !ok {
+		return
+	}
+	rssUrl := fmt.Sprintf("/v1/collections/%s/rss", collection.ID)
+	buffer := bytes.Buffer{}
+	if err := pages.RenderCollection(&buffer, models.NewCollectionPageFromDatabase(collection, owner, rssUrl, posts)); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "PAGE_RENDER_ERROR", fmt.Sprintf("Error rendering collection page: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// HandleGetCollectionRSS serves the public RSS feed of a collection.
+func (apiCfg *ApiConfig) HandleGetCollectionRSS(w http.ResponseWriter, r *http.Request) {
+	collection, owner, posts, ok := apiCfg.getPublicCollection(w, r)
+	if !ok {
+		return
+	}
+	pageUrl := apiCfg.absoluteUrl(fmt.Sprintf("/v1/collections/%s/page", collection.ID))
+	feed := models.NewOutputFeedFromDatabase(collection.ID, collection.Title, owner.Name, apiCfg.absoluteUrl(r.URL.EscapedPath()), pageUrl, posts)
+	if collection.Description.Valid {
+		feed.Description = collection.Description.String
+	}
+	buffer := bytes.Buffer{}
+	if err := syndication.Write(&buffer, syndication.FormatRSS, feed); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "FEED_WRITE_ERROR", fmt.Sprintf("Error writing feed: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", syndication.ContentTypes[syndication.FormatRSS])
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// getPublicCollection returns the collection of the request with its owner
+// and its latest posts.
+func (apiCfg *ApiConfig) getPublicCollection(w http.ResponseWriter, r *http.Request) (database.Collection, database.User, []database.Post, bool) {
+	params := models.CollectionIDParams{}
+	if err := params.Decode(chi.URLParam(r, "collectionID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return database.Collection{}, database.User{}, nil, false
+	}
+	collection, ok := apiCfg.getCollection(w, r, params.ID)
+	if !ok {
+		return collection, database.User{}, nil, false
+	}
+	owner, err := apiCfg.DATABASE.GetUser(r.Context(), collection.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting user: %v", err))
+		return collection, owner, nil, false
+	}
+	posts, err := apiCfg.DATABASE.GetCollectionPosts(r.Context(), database.GetCollectionPostsParams{
+		CollectionID: collection.ID,
+		Limit:        publicCollectionSize,
+		Offset:       0,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return collection, owner, nil, false
+	}
+	return collection, owner, posts, true
+}
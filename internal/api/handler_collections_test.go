@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+func TestCollectionRSSLinksUseTheBaseUrl(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	owner := createTestUser(t, apiCfg, "Ada")
+	collection, err := apiCfg.DATABASE.CreateCollection(context.Background(), database.CreateCollectionParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    owner.ID,
+		Title:     "Reading list",
+	})
+	if err != nil {
+		t.Fatalf("Error creating collection: %v", err)
+	}
+
+	collectionID := collection.ID.String()
+	r := newTestRequest(http.MethodGet, "/v1/collections/"+collectionID+"/rss", "", "collectionID", collectionID)
+	r.Host = "attacker.example.net"
+	w := httptest.NewRecorder()
+	apiCfg.HandleGetCollectionRSS(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("HandleGetCollectionRSS() status = %d, body %s", w.Code, w.Body)
+	}
+	body := w.Body.String()
+	for _, link := range []string{
+		`<link>` + testBaseUrl + `/v1/collections/` + collectionID + `/page</link>`,
+		`href="` + testBaseUrl + `/v1/collections/` + collectionID + `/rss"`,
+	} {
+		if !strings.Contains(body, link) {
+			t.Errorf("HandleGetCollectionRSS() has no %s:\n%s", link, body)
+		}
+	}
+	if strings.Contains(body, r.Host) {
+		t.Errorf("HandleGetCollectionRSS() links to the request host:\n%s", body)
+	}
+}
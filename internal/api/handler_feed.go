@@ -1,10 +1,13 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
 	"github.com/mellomaths/rss-aggregator/internal/models"
@@ -22,13 +25,31 @@ func (apiCfg *ApiConfig) HandleCreateFeed(w http.ResponseWriter, r *http.Request
 		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 		return
 	}
-	feed, err := apiCfg.DATABASE.CreateFeed(r.Context(), database.CreateFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
-		Name:      params.Name,
-		Url:       params.Url,
-		UserID:    user.ID,
+	// The creator follows the feed, in the same transaction as its creation.
+	var feed database.Feed
+	err = apiCfg.withTx(r.Context(), func(queries *database.Queries) error {
+		var err error
+		feed, err = queries.CreateFeed(r.Context(), database.CreateFeedParams{
+			ID:               uuid.New(),
+			CreatedAt:        time.Now().UTC(),
+			UpdatedAt:        time.Now().UTC(),
+			Name:             params.Name,
+			Url:              params.Url,
+			UserID:           user.ID,
+			FetchFullContent: params.FetchFullContent,
+			CanonicalUrl:     params.CanonicalUrl,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = queries.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			FeedID:    feed.ID,
+		})
+		return err
 	})
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating feed: %v", err))
@@ -43,19 +64,193 @@ func (apiCfg *ApiConfig) HandleGetAllFeeds(w http.ResponseWriter, r *http.Reques
 		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting feeds: %v", err))
 		return
 	}
+	if err := pagination.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	params := models.GetFeedsParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_QUERY_PARAMS", fmt.Sprintf("Error getting feeds: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
 	feeds, err := apiCfg.DATABASE.GetAllFeeds(r.Context(), database.GetAllFeedsParams{
+		Query:    params.Query,
+		Language: params.Language,
+		Sort:     params.Sort,
+		Limit:    pagination.Limit,
+		Offset:   pagination.Offset,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feeds: %v", err))
+		return
+	}
+	total, err := apiCfg.DATABASE.CountFeeds(r.Context(), database.CountFeedsParams{
+		Query:    params.Query,
+		Language: params.Language,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting feeds: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), pagination.Offset, pagination.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewFeedsFromDatabase(feeds), total, pagination))
+}
+
+func (apiCfg *ApiConfig) HandleGetFeed(w http.ResponseWriter, r *http.Request) {
+	params := models.GetFeedParams{}
+	if err := params.Decode(chi.URLParam(r, "feedID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	feed, err := apiCfg.DATABASE.GetFeed(r.Context(), params.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed: %v", err))
+		return
+	}
+	stats, err := apiCfg.DATABASE.GetFeedStats(r.Context(), database.GetFeedStatsParams{
+		FeedID:      feed.ID,
+		RecentSince: models.FeedStatsSince(time.Now().UTC()),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed stats: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFeedWithStatsFromDatabase(feed, stats))
+}
+
+// HandleGetFeedPosts lists the posts of any feed, followed or not, newest first.
+func (apiCfg *ApiConfig) HandleGetFeedPosts(w http.ResponseWriter, r *http.Request) {
+	params := models.GetFeedParams{}
+	if err := params.Decode(chi.URLParam(r, "feedID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	pagination := models.PaginatedParams{}
+	if err := pagination.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	if err := pagination.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetFeed(r.Context(), params.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed: %v", err))
+		return
+	}
+	posts, err := apiCfg.DATABASE.GetPostsForFeed(r.Context(), database.GetPostsForFeedParams{
+		FeedID: params.ID,
 		Limit:  pagination.Limit,
 		Offset: pagination.Offset,
 	})
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feeds: %v", err))
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	total, err := apiCfg.DATABASE.CountPostsForFeed(r.Context(), params.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting posts: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), pagination.Offset, pagination.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewPostsFromDatabase(posts), total, pagination))
+}
+
+func (apiCfg *ApiConfig) HandleUpdateFeed(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.UpdateFeedParams{}
+	if err := params.Decode(chi.URLParam(r, "feedID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	feed, ok := apiCfg.getManagedFeed(w, r, user, params.ID)
+	if !ok {
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	feed, err := apiCfg.DATABASE.UpdateFeed(r.Context(), params.ApplyTo(feed))
+	if isUniqueViolation(err, "feeds_canonical_url_key") {
+		respondWithError(w, http.StatusConflict, "FEED_URL_CONFLICT", "Another feed already has this url")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error updating feed: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFeedFromDatabase(feed))
+}
+
+// HandleDeleteFeed deletes a feed with its posts and follows. A feed followed
+// by other users is only deleted by an admin passing force=true.
+func (apiCfg *ApiConfig) HandleDeleteFeed(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.DeleteFeedParams{}
+	if err := params.Decode(chi.URLParam(r, "feedID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	feed, ok := apiCfg.getManagedFeed(w, r, user, params.ID)
+	if !ok {
+		return
+	}
+	if params.Force && !user.IsAdmin {
+		respondWithError(w, http.StatusForbidden, "FORBIDDEN", "Only an admin can force the deletion of a feed")
 		return
 	}
-	data := models.NewFeedsFromDatabase(feeds)
-	respondWithJson(w, http.StatusOK, models.Paginated[*models.Feed]{
-		Data:   data,
-		Total:  len(data),
-		Offset: int(pagination.Offset),
-		Limit:  int(pagination.Limit),
+	// The posts go with the feed, and so do the stars and collection entries
+	// of other users on them.
+	others, err := apiCfg.DATABASE.CountOtherFeedUsers(r.Context(), database.CountOtherFeedUsersParams{
+		FeedID: feed.ID,
+		UserID: user.ID,
 	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting feed users: %v", err))
+		return
+	}
+	if others.Followers > 0 && !params.Force {
+		respondWithError(w, http.StatusConflict, "FEED_HAS_FOLLOWERS", fmt.Sprintf("Feed is followed by %d other users", others.Followers))
+		return
+	}
+	if others.SavingUsers > 0 && !params.Force {
+		respondWithError(w, http.StatusConflict, "FEED_HAS_SAVED_POSTS", fmt.Sprintf("Posts of the feed are starred or collected by %d other users", others.SavingUsers))
+		return
+	}
+	if err := apiCfg.DATABASE.DeleteFeed(r.Context(), feed.ID); err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting feed: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+// getManagedFeed returns the feed when the user created it or is an admin,
+// responding with the error otherwise.
+func (apiCfg *ApiConfig) getManagedFeed(w http.ResponseWriter, r *http.Request, user database.User, feedID uuid.UUID) (database.Feed, bool) {
+	feed, err := apiCfg.DATABASE.GetFeed(r.Context(), feedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return database.Feed{}, false
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed: %v", err))
+		return database.Feed{}, false
+	}
+	if feed.UserID != user.ID && !user.IsAdmin {
+		respondWithError(w, http.StatusForbidden, "FORBIDDEN", "Only the creator of the feed or an admin can manage it")
+		return database.Feed{}, false
+	}
+	return feed, true
 }
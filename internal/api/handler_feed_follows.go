@@ -1,6 +1,8 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -41,19 +43,87 @@ func (apiCfg *ApiConfig) HandleGetFeedsFollowedByUser(w http.ResponseWriter, r *
 		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 		return
 	}
-	feedsFollowed, err := apiCfg.DATABASE.GetFeedsFollowedByUser(r.Context(), user.ID)
+	params := models.GetFeedFollowsParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_QUERY_PARAMS", err.Error())
+		return
+	}
+	feedsFollowed, err := apiCfg.DATABASE.GetFeedsFollowedByUser(r.Context(), database.GetFeedsFollowedByUserParams{
+		UserID:     user.ID,
+		CategoryID: params.CategoryID,
+		Limit:      pagination.Limit,
+		Offset:     pagination.Offset,
+	})
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feeds followed by user: %v", err))
 		return
 	}
-	data := models.NewFeedFollowsFromDatabase(feedsFollowed)
-	total := len(data)
-	respondWithJson(w, http.StatusOK, models.Paginated[*models.FeedFollow]{
-		Data:   data,
-		Total:  total,
-		Offset: int(pagination.Offset),
-		Limit:  int(pagination.Limit),
+	total, err := apiCfg.DATABASE.CountFeedsFollowedByUser(r.Context(), database.CountFeedsFollowedByUserParams{
+		UserID:     user.ID,
+		CategoryID: params.CategoryID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting feeds followed by user: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), pagination.Offset, pagination.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewFeedFollowsFromDatabase(feedsFollowed), total, pagination))
+}
+
+func (apiCfg *ApiConfig) HandleSetFeedFollowCategory(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.SetFeedFollowCategoryParams{}
+	if err := params.Decode(chi.URLParam(r, "feedFollowID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	categoryID := uuid.NullUUID{}
+	if params.CategoryID != nil {
+		categoryID = uuid.NullUUID{UUID: *params.CategoryID, Valid: true}
+	}
+	feedFollow, err := apiCfg.DATABASE.SetFeedFollowCategory(r.Context(), database.SetFeedFollowCategoryParams{
+		CategoryID: categoryID,
+		ID:         params.ID,
+		UserID:     user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed follow or category not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error moving feed follow: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFeedFollowFromDatabase(feedFollow))
+}
+
+func (apiCfg *ApiConfig) HandleUpdateFeedFollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.UpdateFeedFollowParams{}
+	if err := params.Decode(chi.URLParam(r, "feedFollowID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	feedFollow, err := apiCfg.DATABASE.GetFeedFollow(r.Context(), database.GetFeedFollowParams{
+		ID:     params.ID,
+		UserID: user.ID,
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed follow not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed follow: %v", err))
+		return
+	}
+	feedFollow, err = apiCfg.DATABASE.UpdateFeedFollow(r.Context(), params.ApplyTo(feedFollow))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error updating feed follow: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFeedFollowFromDatabase(feedFollow))
 }
 
 func (apiCfg *ApiConfig) HandleDeleteFeedFollow(w http.ResponseWriter, r *http.Request, user database.User) {
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+const feedIconMaxAge = 24 * 60 * 60
+
+func (apiCfg *ApiConfig) HandleGetFeedIcon(w http.ResponseWriter, r *http.Request) {
+	params := models.GetFeedIconParams{}
+	if err := params.Decode(chi.URLParam(r, "feedID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	icon, err := apiCfg.DATABASE.GetFeedIcon(r.Context(), params.FeedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed icon not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed icon: %v", err))
+		return
+	}
+	etag := `"` + icon.Etag + `"`
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", feedIconMaxAge))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", icon.UpdatedAt.UTC().Format(http.TimeFormat))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", icon.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(icon.Data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(icon.Data)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+func TestDeleteFeedKeepsOtherUsersPosts(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		save func(t *testing.T, apiCfg *ApiConfig, user database.User, post database.Post)
+		want int
+	}{
+		{
+			name: "unsaved posts",
+			save: func(t *testing.T, apiCfg *ApiConfig, user database.User, post database.Post) {},
+			want: http.StatusNoContent,
+		},
+		{
+			name: "post starred by another user",
+			save: func(t *testing.T, apiCfg *ApiConfig, user database.User, post database.Post) {
+				err := apiCfg.DATABASE.StarPost(ctx, database.StarPostParams{UserID: user.ID, PostID: post.ID, StarredAt: time.Now().UTC()})
+				if err != nil {
+					t.Fatalf("Error starring post: %v", err)
+				}
+			},
+			want: http.StatusConflict,
+		},
+		{
+			name: "post collected by another user",
+			save: func(t *testing.T, apiCfg *ApiConfig, user database.User, post database.Post) {
+				collection, err := apiCfg.DATABASE.CreateCollection(ctx, database.CreateCollectionParams{
+					ID:        uuid.New(),
+					CreatedAt: time.Now().UTC(),
+					UpdatedAt: time.Now().UTC(),
+					UserID:    user.ID,
+					Title:     "Reading list",
+				})
+				if err != nil {
+					t.Fatalf("Error creating collection: %v", err)
+				}
+				err = apiCfg.DATABASE.AddPostToCollection(ctx, database.AddPostToCollectionParams{CollectionID: collection.ID, PostID: post.ID, AddedAt: time.Now().UTC()})
+				if err != nil {
+					t.Fatalf("Error adding post to collection: %v", err)
+				}
+			},
+			want: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiCfg := newTestApiConfig(t)
+			creator := createTestUser(t, apiCfg, "Ada")
+			other := createTestUser(t, apiCfg, "Grace")
+			feed := createTestFeed(t, apiCfg, creator, "https://example.com/feed")
+			post := createTestPost(t, apiCfg, feed, "Saved")
+			err := apiCfg.DATABASE.StarPost(ctx, database.StarPostParams{UserID: creator.ID, PostID: post.ID, StarredAt: time.Now().UTC()})
+			if err != nil {
+				t.Fatalf("Error starring post: %v", err)
+			}
+			// The other user does not follow the feed, only its saved posts hold it.
+			tt.save(t, apiCfg, other, post)
+
+			feedID := feed.ID.String()
+			w := httptest.NewRecorder()
+			apiCfg.HandleDeleteFeed(w, newTestRequest(http.MethodDelete, "/v1/feeds/"+feedID, "", "feedID", feedID), creator)
+			if w.Code != tt.want {
+				t.Fatalf("HandleDeleteFeed() status = %d, want %d, body %s", w.Code, tt.want, w.Body)
+			}
+			_, err = apiCfg.DATABASE.GetPost(ctx, post.ID)
+			if deleted := err != nil; deleted != (tt.want == http.StatusNoContent) {
+				t.Errorf("HandleDeleteFeed() deleted the post = %v, want %v", deleted, tt.want == http.StatusNoContent)
+			}
+		})
+	}
+}
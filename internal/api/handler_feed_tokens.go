@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+	"github.com/mellomaths/rss-aggregator/internal/syndication"
+)
+
+// outputFeedSize is the number of posts listed by an output feed.
+const outputFeedSize = 50
+
+func (apiCfg *ApiConfig) HandleCreateFeedToken(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateFeedTokenParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if params.CategoryID != nil {
+		_, err := apiCfg.DATABASE.GetCategory(r.Context(), database.GetCategoryParams{
+			ID:     *params.CategoryID,
+			UserID: user.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Category not found")
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting category: %v", err))
+			return
+		}
+	}
+	feedToken, err := apiCfg.DATABASE.CreateFeedToken(r.Context(), database.CreateFeedTokenParams{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UserID:     user.ID,
+		Kind:       params.Kind,
+		CategoryID: params.NullCategoryID(),
+		Tag:        params.NullTag(),
+		Token:      generateRandomHex(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating feed token: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewFeedTokenFromDatabase(feedToken))
+}
+
+func (apiCfg *ApiConfig) HandleGetFeedTokens(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedTokens, err := apiCfg.DATABASE.GetFeedTokensForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed tokens: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFeedTokensFromDatabase(feedTokens))
+}
+
+func (apiCfg *ApiConfig) HandleDeleteFeedToken(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.FeedTokenIDParams{}
+	if err := params.Decode(chi.URLParam(r, "feedTokenID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.DeleteFeedToken(r.Context(), database.DeleteFeedTokenParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting feed token: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+// HandleGetOutputFeed serves the posts of a user as an RSS, Atom or JSON
+// feed. Feed readers can't send an API key, so the request is authenticated
+// by the secret token of the feed, given in the URL.
+func (apiCfg *ApiConfig) HandleGetOutputFeed(w http.ResponseWriter, r *http.Request) {
+	params := models.GetOutputFeedParams{}
+	if err := params.Decode(chi.URLParam(r, "userID"), chi.URLParam(r, "file"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	feedToken, err := apiCfg.DATABASE.GetFeedTokenByToken(r.Context(), params.Token)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !params.Matches(feedToken)) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed token: %v", err))
+		return
+	}
+	user, err := apiCfg.DATABASE.GetUser(r.Context(), feedToken.UserID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting user: %v", err))
+		return
+	}
+	title, posts, err := apiCfg.getOutputFeedPosts(r, feedToken)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	// The token stays out of the document, which feed readers may share.
+	feed := models.NewOutputFeedFromDatabase(feedToken.ID, title, user.Name, apiCfg.absoluteUrl(r.URL.EscapedPath()), apiCfg.absoluteUrl("/"), posts)
+	buffer := bytes.Buffer{}
+	if err := syndication.Write(&buffer, params.Format, feed); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "FEED_WRITE_ERROR", fmt.Sprintf("Error writing feed: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", syndication.ContentTypes[params.Format])
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// getOutputFeedPosts returns the title and the latest posts of the feed a
+// token gives access to.
+func (apiCfg *ApiConfig) getOutputFeedPosts(r *http.Request, feedToken database.FeedToken) (string, []database.Post, error) {
+	if feedToken.Kind == models.OutputFeedStarred {
+		posts, err := apiCfg.DATABASE.GetStarredPostsForUser(r.Context(), database.GetStarredPostsForUserParams{
+			UserID: feedToken.UserID,
+			Limit:  outputFeedSize,
+			Offset: 0,
+		})
+		return "Starred posts", posts, err
+	}
+	title := "Timeline"
+	switch feedToken.Kind {
+	case models.OutputFeedCategory:
+		category, err := apiCfg.DATABASE.GetCategory(r.Context(), database.GetCategoryParams{
+			ID:     feedToken.CategoryID.UUID,
+			UserID: feedToken.UserID,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		title = "Category " + category.Name
+	case models.OutputFeedTag:
+		title = "Tag " + feedToken.Tag.String
+	}
+	posts, err := apiCfg.DATABASE.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+		UserID:     feedToken.UserID,
+		Status:     "all",
+		CategoryID: feedToken.CategoryID,
+		Tag:        feedToken.Tag,
+		Limit:      outputFeedSize,
+		Offset:     0,
+	})
+	return title, posts, err
+}
+
+// absoluteUrl returns the absolute URL of a path of the API. It is built from
+// the configured base URL rather than the Host header, which the client sets.
+func (apiCfg *ApiConfig) absoluteUrl(path string) string {
+	return apiCfg.BaseUrl + path
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/filters"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+// maxFilterRules limits the filter rules of a user.
+const maxFilterRules = 100
+
+var (
+	errTooManyFilterRules    = errors.New("too many filter rules")
+	errTooManyFilterRuleRuns = errors.New("too many filter rule runs")
+	errFilterRuleFeed        = errors.New("filter rule feed not followed")
+)
+
+// checkFilterRuleFeed returns errFilterRuleFeed when a rule is limited to a
+// feed the user does not follow.
+func checkFilterRuleFeed(ctx context.Context, queries *database.Queries, user database.User, feedID uuid.NullUUID) error {
+	if !feedID.Valid {
+		return nil
+	}
+	following, err := queries.IsFollowingFeed(ctx, database.IsFollowingFeedParams{
+		UserID: user.ID,
+		FeedID: feedID.UUID,
+	})
+	if err != nil {
+		return err
+	}
+	if !following {
+		return errFilterRuleFeed
+	}
+	return nil
+}
+
+func (apiCfg *ApiConfig) HandleCreateFilterRule(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateFilterRuleParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	// The user is locked until the rule is inserted, so concurrent creations
+	// cannot all pass the limit.
+	var rule database.FilterRule
+	err := apiCfg.withTx(r.Context(), func(queries *database.Queries) error {
+		if err := queries.LockUser(r.Context(), user.ID); err != nil {
+			return err
+		}
+		count, err := queries.CountFilterRulesForUser(r.Context(), user.ID)
+		if err != nil {
+			return err
+		}
+		if count >= maxFilterRules {
+			return errTooManyFilterRules
+		}
+		if err := checkFilterRuleFeed(r.Context(), queries, user, params.NullFeedID()); err != nil {
+			return err
+		}
+		rule, err = queries.CreateFilterRule(r.Context(), database.CreateFilterRuleParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			FeedID:    params.NullFeedID(),
+			Field:     params.Field,
+			MatchType: params.MatchType,
+			Pattern:   params.Pattern,
+			Actions:   params.Actions,
+			Tag:       params.NullTag(),
+		})
+		return err
+	})
+	if errors.Is(err, errTooManyFilterRules) {
+		respondWithError(w, http.StatusTooManyRequests, "TOO_MANY_RULES", fmt.Sprintf("At most %d filter rules can be created", maxFilterRules))
+		return
+	}
+	if errors.Is(err, errFilterRuleFeed) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating filter rule: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewFilterRuleFromDatabase(rule))
+}
+
+func (apiCfg *ApiConfig) HandleGetFilterRules(w http.ResponseWriter, r *http.Request, user database.User) {
+	rules, err := apiCfg.DATABASE.GetFilterRulesForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting filter rules: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFilterRulesFromDatabase(rules))
+}
+
+func (apiCfg *ApiConfig) HandleUpdateFilterRule(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.UpdateFilterRuleParams{}
+	if err := params.Decode(chi.URLParam(r, "filterRuleID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	err := checkFilterRuleFeed(r.Context(), apiCfg.DATABASE, user, params.NullFeedID())
+	if errors.Is(err, errFilterRuleFeed) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Feed not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed follow: %v", err))
+		return
+	}
+	rule, err := apiCfg.DATABASE.UpdateFilterRule(r.Context(), database.UpdateFilterRuleParams{
+		ID:        params.ID,
+		UserID:    user.ID,
+		FeedID:    params.NullFeedID(),
+		Field:     params.Field,
+		MatchType: params.MatchType,
+		Pattern:   params.Pattern,
+		Actions:   params.Actions,
+		Tag:       params.NullTag(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Filter rule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error updating filter rule: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFilterRuleFromDatabase(rule))
+}
+
+func (apiCfg *ApiConfig) HandleDeleteFilterRule(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.FilterRuleIDParams{}
+	if err := params.Decode(chi.URLParam(r, "filterRuleID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.DeleteFilterRule(r.Context(), database.DeleteFilterRuleParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting filter rule: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+// maxActiveFilterRuleRuns limits the runs of a user pending or running at once.
+const maxActiveFilterRuleRuns = 3
+
+// HandleApplyFilterRule queues a run of a rule on the posts already stored,
+// for posts scraped before the rule was created. Its progress is read with
+// HandleGetFilterRuleRun.
+func (apiCfg *ApiConfig) HandleApplyFilterRule(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.FilterRuleIDParams{}
+	if err := params.Decode(chi.URLParam(r, "filterRuleID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	dbRule, err := apiCfg.DATABASE.GetFilterRule(r.Context(), database.GetFilterRuleParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Filter rule not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting filter rule: %v", err))
+		return
+	}
+	if _, err := filters.Compile(dbRule); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	var run database.FilterRuleRun
+	err = apiCfg.withTx(r.Context(), func(queries *database.Queries) error {
+		if err := queries.LockUser(r.Context(), user.ID); err != nil {
+			return err
+		}
+		active, err := queries.CountActiveFilterRuleRunsForUser(r.Context(), user.ID)
+		if err != nil {
+			return err
+		}
+		if active >= maxActiveFilterRuleRuns {
+			return errTooManyFilterRuleRuns
+		}
+		run, err = queries.CreateFilterRuleRun(r.Context(), database.CreateFilterRuleRunParams{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now().UTC(),
+			UpdatedAt:    time.Now().UTC(),
+			FilterRuleID: dbRule.ID,
+			UserID:       user.ID,
+			Status:       models.FilterRuleRunPending,
+		})
+		return err
+	})
+	if errors.Is(err, errTooManyFilterRuleRuns) {
+		respondWithError(w, http.StatusTooManyRequests, "TOO_MANY_RUNS", fmt.Sprintf("At most %d filter rule runs can be pending or running at once", maxActiveFilterRuleRuns))
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating filter rule run: %v", err))
+		return
+	}
+	apiCfg.filterRuleRuns.Notify()
+	respondWithJson(w, http.StatusAccepted, models.NewFilterRuleRunFromDatabase(run))
+}
+
+func (apiCfg *ApiConfig) HandleGetFilterRuleRun(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.FilterRuleRunIDParams{}
+	if err := params.Decode(chi.URLParam(r, "filterRuleID"), chi.URLParam(r, "runID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	run, err := apiCfg.DATABASE.GetFilterRuleRun(r.Context(), database.GetFilterRuleRunParams{
+		ID:           params.ID,
+		FilterRuleID: params.FilterRuleID,
+		UserID:       user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Filter rule run not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting filter rule run: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewFilterRuleRunFromDatabase(run))
+}
+
+// runFilterRule applies a rule to the posts already stored, as a job of the
+// filterRuleRuns queue.
+func (apiCfg *ApiConfig) runFilterRule(ctx context.Context, run database.FilterRuleRun) error {
+	dbRule, err := apiCfg.DATABASE.GetFilterRule(ctx, database.GetFilterRuleParams{
+		ID:     run.FilterRuleID,
+		UserID: run.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	rule, err := filters.Compile(dbRule)
+	if err != nil {
+		return err
+	}
+	matched, err := rule.ApplyToExistingPosts(ctx, apiCfg.DATABASE)
+	if err != nil {
+		return err
+	}
+	return apiCfg.DATABASE.SetFilterRuleRunStatus(ctx, database.SetFilterRuleRunStatusParams{
+		ID:      run.ID,
+		Status:  models.FilterRuleRunCompleted,
+		Matched: matched,
+	})
+}
+
+func (apiCfg *ApiConfig) failFilterRuleRun(ctx context.Context, run database.FilterRuleRun, runErr error) {
+	err := apiCfg.DATABASE.SetFilterRuleRunStatus(ctx, database.SetFilterRuleRunStatusParams{
+		ID:     run.ID,
+		Status: models.FilterRuleRunFailed,
+		Error:  sql.NullString{String: runErr.Error(), Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error recording failure of filter rule run %v: %v", run.ID, err)
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateFilterRuleFeed(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	user := createTestUser(t, apiCfg, "Ada")
+	followed := createTestFeed(t, apiCfg, user, "https://example.com/feed")
+	notFollowed := createTestFeed(t, apiCfg, createTestUser(t, apiCfg, "Grace"), "https://other.example.com/feed")
+
+	tests := []struct {
+		name   string
+		feedID uuid.UUID
+		want   int
+	}{
+		{"followed feed", followed.ID, http.StatusCreated},
+		{"feed not followed", notFollowed.ID, http.StatusNotFound},
+		{"missing feed", uuid.New(), http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"feed_id": %q, "field": "title", "match_type": "contains", "pattern": "sponsored", "actions": ["read"]}`, tt.feedID)
+			w := httptest.NewRecorder()
+			apiCfg.HandleCreateFilterRule(w, newTestRequest(http.MethodPost, "/v1/filters", body), user)
+			if w.Code != tt.want {
+				t.Errorf("HandleCreateFilterRule() status = %d, want %d, body %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestCreateFilterRuleLimit(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	user := createTestUser(t, apiCfg, "Ada")
+	body := `{"field": "title", "match_type": "contains", "pattern": "sponsored", "actions": ["read"]}`
+	for i := 0; i < maxFilterRules; i++ {
+		w := httptest.NewRecorder()
+		apiCfg.HandleCreateFilterRule(w, newTestRequest(http.MethodPost, "/v1/filters", body), user)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("HandleCreateFilterRule() status = %d, body %s", w.Code, w.Body)
+		}
+	}
+	w := httptest.NewRecorder()
+	apiCfg.HandleCreateFilterRule(w, newTestRequest(http.MethodPost, "/v1/filters", body), user)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("HandleCreateFilterRule() beyond the limit status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
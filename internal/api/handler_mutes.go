@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleCreateMute(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateMuteParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	mute, err := apiCfg.DATABASE.CreateMute(r.Context(), database.CreateMuteParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		Kind:      params.Kind,
+		Value:     params.Value,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating mute: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewMuteFromDatabase(mute))
+}
+
+func (apiCfg *ApiConfig) HandleGetMutes(w http.ResponseWriter, r *http.Request, user database.User) {
+	mutes, err := apiCfg.DATABASE.GetMutesForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting mutes: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewMutesFromDatabase(mutes))
+}
+
+func (apiCfg *ApiConfig) HandleDeleteMute(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.DeleteMuteParams{}
+	if err := params.Decode(chi.URLParam(r, "muteID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.DeleteMute(r.Context(), database.DeleteMuteParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error deleting mute: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
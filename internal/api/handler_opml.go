@@ -0,0 +1,238 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+	"github.com/mellomaths/rss-aggregator/internal/opml"
+)
+
+// maxActiveOpmlImports limits the imports of a user pending or running at once.
+const maxActiveOpmlImports = 1
+
+// HandleImportOpml queues a background job following the feeds of an OPML
+// document, whose progress is read with HandleGetOpmlImport. Each feed is
+// stored as a pending result with the job, so an interrupted import resumes
+// from the feeds not imported yet.
+func (apiCfg *ApiConfig) HandleImportOpml(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.ImportOpmlParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	active, err := apiCfg.DATABASE.CountActiveOpmlImportsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting OPML imports: %v", err))
+		return
+	}
+	if active >= maxActiveOpmlImports {
+		respondWithError(w, http.StatusTooManyRequests, "TOO_MANY_IMPORTS", fmt.Sprintf("At most %d OPML imports can be pending or running at once", maxActiveOpmlImports))
+		return
+	}
+	var job database.OpmlImport
+	err = apiCfg.withTx(r.Context(), func(queries *database.Queries) error {
+		var err error
+		job, err = queries.CreateOpmlImport(r.Context(), database.CreateOpmlImportParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			Status:    models.OpmlImportPending,
+			Total:     int32(len(params.Subscriptions)),
+		})
+		if err != nil {
+			return err
+		}
+		for i, subscription := range params.Subscriptions {
+			err := queries.CreateOpmlImportResult(r.Context(), database.CreateOpmlImportResultParams{
+				ID:       uuid.New(),
+				ImportID: job.ID,
+				Position: int32(i),
+				Title:    subscription.Title,
+				Url:      subscription.Url,
+				Category: sql.NullString{String: subscription.Category, Valid: subscription.Category != ""},
+				Status:   models.OpmlResultPending,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating OPML import: %v", err))
+		return
+	}
+	apiCfg.opmlImports.Notify()
+	respondWithJson(w, http.StatusAccepted, models.NewOpmlImportFromDatabase(job, nil))
+}
+
+func (apiCfg *ApiConfig) HandleGetOpmlImport(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.OpmlImportIDParams{}
+	if err := params.Decode(chi.URLParam(r, "jobID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	job, err := apiCfg.DATABASE.GetOpmlImport(r.Context(), database.GetOpmlImportParams{
+		ID:     params.ID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "OPML import not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting OPML import: %v", err))
+		return
+	}
+	results, err := apiCfg.DATABASE.GetOpmlImportResults(r.Context(), job.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting OPML import results: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewOpmlImportFromDatabase(job, results))
+}
+
+// HandleExportOpml responds with the feeds followed by the user as an OPML
+// 2.0 document, grouped by category.
+func (apiCfg *ApiConfig) HandleExportOpml(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedFollows, err := apiCfg.DATABASE.GetFeedFollowsForExport(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feeds followed by user: %v", err))
+		return
+	}
+	buffer := bytes.Buffer{}
+	if err := models.NewOpmlFromDatabase(user, feedFollows).Write(&buffer); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "OPML_EXPORT_ERROR", fmt.Sprintf("Error writing OPML document: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="subscriptions.opml"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+// runOpmlImport follows the feeds of an import still pending in turn,
+// recording the result of each feed.
+func (apiCfg *ApiConfig) runOpmlImport(ctx context.Context, job database.OpmlImport) error {
+	user, err := apiCfg.DATABASE.GetUser(ctx, job.UserID)
+	if err != nil {
+		return err
+	}
+	results, err := apiCfg.DATABASE.GetPendingOpmlImportResults(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		status, importErr := apiCfg.importOpmlSubscription(ctx, user, opml.Subscription{
+			Title:    result.Title,
+			Url:      result.Url,
+			Category: result.Category.String,
+		})
+		err := apiCfg.DATABASE.SetOpmlImportResultStatus(ctx, database.SetOpmlImportResultStatusParams{
+			ID:     result.ID,
+			Status: status,
+			Error:  errorToNullString(importErr),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return apiCfg.setOpmlImportStatus(ctx, job, models.OpmlImportCompleted, nil)
+}
+
+func (apiCfg *ApiConfig) failOpmlImport(ctx context.Context, job database.OpmlImport, jobErr error) {
+	if err := apiCfg.setOpmlImportStatus(ctx, job, models.OpmlImportFailed, jobErr); err != nil {
+		log.Printf("Error recording failure of OPML import %v: %v", job.ID, err)
+	}
+}
+
+func (apiCfg *ApiConfig) setOpmlImportStatus(ctx context.Context, job database.OpmlImport, status string, jobErr error) error {
+	return apiCfg.DATABASE.SetOpmlImportStatus(ctx, database.SetOpmlImportStatusParams{
+		ID:     job.ID,
+		Status: status,
+		Error:  errorToNullString(jobErr),
+	})
+}
+
+// importOpmlSubscription follows one feed of an import and, when the user did
+// not follow it yet, files it under its category with its OPML title.
+func (apiCfg *ApiConfig) importOpmlSubscription(ctx context.Context, user database.User, subscription opml.Subscription) (string, error) {
+	params := models.CreateSubscriptionParams{Url: subscription.Url, Name: subscription.Title}
+	if err := params.Validate(); err != nil {
+		return models.OpmlResultFailed, err
+	}
+	_, err := apiCfg.DATABASE.GetFeedByCanonicalUrl(ctx, params.CanonicalUrl)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := params.Discover(); err != nil {
+			return models.OpmlResultFailed, err
+		}
+	} else if err != nil {
+		return models.OpmlResultFailed, err
+	}
+	s, err := apiCfg.subscribe(ctx, params, user)
+	if err != nil {
+		return models.OpmlResultFailed, err
+	}
+	if !s.followCreated {
+		return models.OpmlResultAlreadyFollowed, nil
+	}
+	if params.Name != "" && params.Name != s.feed.Name {
+		_, err := apiCfg.DATABASE.UpdateFeedFollow(ctx, database.UpdateFeedFollowParams{
+			ID:               s.feedFollow.ID,
+			UserID:           user.ID,
+			CustomTitle:      sql.NullString{String: params.Name, Valid: true},
+			Notes:            s.feedFollow.Notes,
+			Priority:         s.feedFollow.Priority,
+			HideFromTimeline: s.feedFollow.HideFromTimeline,
+		})
+		if err != nil {
+			return models.OpmlResultFailed, err
+		}
+	}
+	if subscription.Category != "" {
+		category, err := apiCfg.DATABASE.UpsertCategory(ctx, database.UpsertCategoryParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			Name:      subscription.Category,
+		})
+		if err != nil {
+			return models.OpmlResultFailed, err
+		}
+		_, err = apiCfg.DATABASE.SetFeedFollowCategory(ctx, database.SetFeedFollowCategoryParams{
+			CategoryID: uuid.NullUUID{UUID: category.ID, Valid: true},
+			ID:         s.feedFollow.ID,
+			UserID:     user.ID,
+		})
+		if err != nil {
+			return models.OpmlResultFailed, err
+		}
+	}
+	if s.feedCreated {
+		return models.OpmlResultCreated, nil
+	}
+	return models.OpmlResultFollowed, nil
+}
+
+func errorToNullString(err error) sql.NullString {
+	if err == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: err.Error(), Valid: true}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleHidePost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostHideParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     params.PostID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.HidePost(r.Context(), database.HidePostParams{
+		UserID:   user.ID,
+		PostID:   params.PostID,
+		HiddenAt: time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error hiding post: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleUnhidePost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostHideParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	err := apiCfg.DATABASE.UnhidePost(r.Context(), database.UnhidePostParams{
+		UserID: user.ID,
+		PostID: params.PostID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error unhiding post: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleMarkPostAsRead(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostReadParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     params.PostID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.MarkPostAsRead(r.Context(), database.MarkPostAsReadParams{
+		UserID: user.ID,
+		PostID: params.PostID,
+		ReadAt: time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error marking post as read: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleMarkPostAsUnread(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostReadParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     params.PostID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.MarkPostAsUnread(r.Context(), database.MarkPostAsUnreadParams{
+		UserID: user.ID,
+		PostID: params.PostID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error marking post as unread: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleMarkPostsAsRead(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.MarkPostsAsReadParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	arg := database.MarkPostsAsReadParams{
+		ReadAt: time.Now().UTC(),
+		UserID: user.ID,
+	}
+	if params.FeedID != nil {
+		arg.FeedID = uuid.NullUUID{UUID: *params.FeedID, Valid: true}
+	}
+	if params.CategoryID != nil {
+		arg.CategoryID = uuid.NullUUID{UUID: *params.CategoryID, Valid: true}
+	}
+	if params.Before != nil {
+		arg.PublishedBefore = sql.NullTime{Time: *params.Before, Valid: true}
+	}
+	if params.BeforePostID != nil {
+		post, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+			ID:     *params.BeforePostID,
+			UserID: user.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+			return
+		}
+		// Both bounds apply when before is given as well.
+		if !arg.PublishedBefore.Valid || post.PublishedAt.Before(arg.PublishedBefore.Time) {
+			arg.PublishedBefore = sql.NullTime{Time: post.PublishedAt, Valid: true}
+		}
+	}
+	affected, err := apiCfg.DATABASE.MarkPostsAsRead(r.Context(), arg)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error marking posts as read: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.MarkPostsAsReadResult{Affected: affected})
+}
+
+func (apiCfg *ApiConfig) HandleGetUnreadCounts(w http.ResponseWriter, r *http.Request, user database.User) {
+	counts, err := apiCfg.DATABASE.GetUnreadCountsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting unread counts: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewUnreadCountsFromDatabase(counts))
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func getUnreadCounts(t *testing.T, apiCfg *ApiConfig, user database.User) map[uuid.UUID]int64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	apiCfg.HandleGetUnreadCounts(w, newTestRequest(http.MethodGet, "/v1/feeds/follows/unread-counts", ""), user)
+	if w.Code != http.StatusOK {
+		t.Fatalf("HandleGetUnreadCounts() status = %d, body %s", w.Code, w.Body)
+	}
+	var counts []models.UnreadCount
+	if err := json.Unmarshal(w.Body.Bytes(), &counts); err != nil {
+		t.Fatalf("Error decoding unread counts: %v", err)
+	}
+	byFeed := map[uuid.UUID]int64{}
+	for _, count := range counts {
+		byFeed[count.FeedID] = count.UnreadCount
+	}
+	return byFeed
+}
+
+func TestUnreadCountsMatchTheTimeline(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	ctx := context.Background()
+	user := createTestUser(t, apiCfg, "Ada")
+	feed := createTestFeed(t, apiCfg, user, "https://example.com/feed")
+	hiddenFeed := createTestFeed(t, apiCfg, createTestUser(t, apiCfg, "Grace"), "https://hidden.example.com/feed")
+	hiddenFollow := followTestFeed(t, apiCfg, user, hiddenFeed)
+	createTestPost(t, apiCfg, feed, "Go news")
+	hiddenPost := createTestPost(t, apiCfg, feed, "Release notes")
+	createTestPost(t, apiCfg, feed, "Weekly crypto roundup")
+	createTestPost(t, apiCfg, hiddenFeed, "Hidden feed post")
+
+	_, err := apiCfg.DATABASE.UpdateFeedFollow(ctx, database.UpdateFeedFollowParams{
+		ID:               hiddenFollow.ID,
+		UserID:           user.ID,
+		HideFromTimeline: true,
+	})
+	if err != nil {
+		t.Fatalf("Error hiding feed from the timeline: %v", err)
+	}
+	want := map[uuid.UUID]int64{feed.ID: 3}
+	if got := getUnreadCounts(t, apiCfg, user); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unread counts = %v, want %v", got, want)
+	}
+
+	err = apiCfg.DATABASE.HidePost(ctx, database.HidePostParams{UserID: user.ID, PostID: hiddenPost.ID, HiddenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("Error hiding post: %v", err)
+	}
+	_, err = apiCfg.DATABASE.CreateMute(ctx, database.CreateMuteParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    user.ID,
+		Kind:      "word",
+		Value:     "crypto",
+	})
+	if err != nil {
+		t.Fatalf("Error creating mute: %v", err)
+	}
+	want = map[uuid.UUID]int64{feed.ID: 1}
+	if got := getUnreadCounts(t, apiCfg, user); !reflect.DeepEqual(got, want) {
+		t.Errorf("unread counts with a hidden and a muted post = %v, want %v", got, want)
+	}
+}
+
+func TestMarkPostAsUnreadNotFound(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	user := createTestUser(t, apiCfg, "Ada")
+	other := createTestUser(t, apiCfg, "Grace")
+	post := createTestPost(t, apiCfg, createTestFeed(t, apiCfg, other, "https://example.com/feed"), "Not followed")
+
+	for _, postID := range []string{post.ID.String(), uuid.NewString()} {
+		w := httptest.NewRecorder()
+		apiCfg.HandleMarkPostAsUnread(w, newTestRequest(http.MethodDelete, "/v1/posts/"+postID+"/read", "", "postID", postID), user)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("HandleMarkPostAsUnread(%v) status = %d, want %d", postID, w.Code, http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleStarPost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostStarParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     params.PostID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.StarPost(r.Context(), database.StarPostParams{
+		UserID:    user.ID,
+		PostID:    params.PostID,
+		StarredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error starring post: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleUnstarPost(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.PostStarParams{}
+	if err := params.Decode(chi.URLParam(r, "postID")); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_URL_PARAMS", err.Error())
+		return
+	}
+	unstarred, err := apiCfg.DATABASE.UnstarPost(r.Context(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: params.PostID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_DELETE_ERROR", fmt.Sprintf("Error unstarring post: %v", err))
+		return
+	}
+	// A starred post stays starred after its feed is unfollowed, so the post is
+	// only looked up when it was not starred.
+	if unstarred == 0 {
+		_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+			ID:     params.PostID,
+			UserID: user.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+			return
+		}
+	}
+	respondWithJson(w, http.StatusNoContent, struct{}{})
+}
+
+func (apiCfg *ApiConfig) HandleGetStarredPosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	paginated := models.PaginatedParams{}
+	if err := paginated.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting starred posts: %v", err))
+		return
+	}
+	if err := paginated.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	posts, err := apiCfg.DATABASE.GetStarredPostsForUser(r.Context(), database.GetStarredPostsForUserParams{
+		UserID: user.ID,
+		Limit:  paginated.Limit,
+		Offset: paginated.Offset,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting starred posts: %v", err))
+		return
+	}
+	total, err := apiCfg.DATABASE.CountStarredPostsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting starred posts: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), paginated.Offset, paginated.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewPostsFromDatabase(posts), total, paginated))
+}
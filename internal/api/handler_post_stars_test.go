@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+func TestStarPostNotFound(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	user := createTestUser(t, apiCfg, "Ada")
+	post := createTestPost(t, apiCfg, createTestFeed(t, apiCfg, createTestUser(t, apiCfg, "Grace"), "https://example.com/feed"), "Not followed")
+
+	for _, postID := range []string{post.ID.String(), uuid.NewString()} {
+		w := httptest.NewRecorder()
+		apiCfg.HandleStarPost(w, newTestRequest(http.MethodPut, "/v1/posts/"+postID+"/star", "", "postID", postID), user)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("HandleStarPost(%v) status = %d, want %d", postID, w.Code, http.StatusNotFound)
+		}
+		w = httptest.NewRecorder()
+		apiCfg.HandleUnstarPost(w, newTestRequest(http.MethodDelete, "/v1/posts/"+postID+"/star", "", "postID", postID), user)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("HandleUnstarPost(%v) status = %d, want %d", postID, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestUnstarPostOfUnfollowedFeed(t *testing.T) {
+	apiCfg := newTestApiConfig(t)
+	user := createTestUser(t, apiCfg, "Ada")
+	feed := createTestFeed(t, apiCfg, createTestUser(t, apiCfg, "Grace"), "https://example.com/feed")
+	feedFollow := followTestFeed(t, apiCfg, user, feed)
+	post := createTestPost(t, apiCfg, feed, "Starred")
+	postID := post.ID.String()
+
+	w := httptest.NewRecorder()
+	apiCfg.HandleStarPost(w, newTestRequest(http.MethodPut, "/v1/posts/"+postID+"/star", "", "postID", postID), user)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("HandleStarPost() status = %d, body %s", w.Code, w.Body)
+	}
+	err := apiCfg.DATABASE.DeleteFeedFollow(context.Background(), database.DeleteFeedFollowParams{ID: feedFollow.ID, UserID: user.ID})
+	if err != nil {
+		t.Fatalf("Error unfollowing feed: %v", err)
+	}
+	w = httptest.NewRecorder()
+	apiCfg.HandleUnstarPost(w, newTestRequest(http.MethodDelete, "/v1/posts/"+postID+"/star", "", "postID", postID), user)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("HandleUnstarPost() of a starred post of an unfollowed feed status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
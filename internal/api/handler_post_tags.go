@@ -0,0 +1,60 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+func (apiCfg *ApiConfig) HandleSetPostTags(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.SetPostTagsParams{}
+	if err := params.Decode(chi.URLParam(r, "postID"), r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     params.PostID,
+		UserID: user.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "RECORD_NOT_FOUND", "Post not found")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting post: %v", err))
+		return
+	}
+	err = apiCfg.DATABASE.SetPostTags(r.Context(), database.SetPostTagsParams{
+		UserID:    user.ID,
+		PostID:    params.PostID,
+		Tags:      params.Tags,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_UPDATE_ERROR", fmt.Sprintf("Error setting post tags: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.PostTags{
+		PostID: params.PostID,
+		Tags:   params.Tags,
+	})
+}
+
+func (apiCfg *ApiConfig) HandleGetTags(w http.ResponseWriter, r *http.Request, user database.User) {
+	tags, err := apiCfg.DATABASE.GetTagsForUser(r.Context(), user.ID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting tags: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusOK, models.NewTagsFromDatabase(tags))
+}
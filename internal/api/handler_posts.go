@@ -1,11 +1,16 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
+	"slices"
 
+	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
 	"github.com/mellomaths/rss-aggregator/internal/models"
+	"github.com/mellomaths/rss-aggregator/internal/postquery"
 )
 
 func (apiCfg *ApiConfig) HandleGetPostsForUser(w http.ResponseWriter, r *http.Request, user database.User) {
@@ -14,20 +19,173 @@ func (apiCfg *ApiConfig) HandleGetPostsForUser(w http.ResponseWriter, r *http.Re
 		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting posts: %v", err))
 		return
 	}
-	posts, err := apiCfg.DATABASE.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+	if err := paginated.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	params := models.GetPostsParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_QUERY_PARAMS", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	var cursor *models.PostCursor
+	if paginated.Cursor != "" {
+		decoded, err := models.DecodePostCursor(paginated.Cursor)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+			return
+		}
+		if decoded.Sort != params.SortKey() {
+			respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", "Error getting posts: cursor was made for another sort order")
+			return
+		}
+		cursor = decoded
+		paginated.Offset = 0
+	}
+	posts, hasMore, err := apiCfg.getPostsPage(r.Context(), user, params, paginated, cursor)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		return
+	}
+	total, err := apiCfg.countPosts(r.Context(), user, params)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting posts: %v", err))
+		return
+	}
+	data := models.NewPostsFromDatabase(posts)
+	response := models.NewPaginated(data, total, paginated)
+	if len(data) > 0 {
+		goingBack := cursor != nil && cursor.Direction == models.CursorPrev
+		if goingBack || hasMore {
+			response.NextCursor = params.Cursor(data[len(data)-1], models.CursorNext).Encode()
+		}
+		if (goingBack && hasMore) || (cursor != nil && !goingBack) || paginated.Offset > 0 {
+			response.PrevCursor = params.Cursor(data[0], models.CursorPrev).Encode()
+		}
+	}
+	if cursor != nil {
+		// The position of a cursor page in the list is unknown.
+		response.HasMore = response.NextCursor != ""
+		setLinkHeader(w, r, cursorPageLinks(response.PrevCursor, response.NextCursor))
+	} else {
+		setLinkHeader(w, r, offsetPageLinks(int(total), paginated.Offset, paginated.Limit))
+	}
+	respondWithJson(w, http.StatusOK, response)
+}
+
+// getPostsPage returns a page of posts in the requested order, starting after
+// the cursor when there is one, and whether more posts exist past the page in
+// the direction of the cursor. Filters beyond status, category and tag, or
+// another order than the newest first, go through the dynamic query.
+func (apiCfg *ApiConfig) getPostsPage(ctx context.Context, user database.User, params models.GetPostsParams, paginated models.PaginatedParams, cursor *models.PostCursor) ([]database.Post, bool, error) {
+	goingBack := cursor != nil && cursor.Direction == models.CursorPrev
+	var posts []database.Post
+	var err error
+	switch {
+	case params.UsesFilterQuery():
+		query := params.FilterQuery(user.ID)
+		query.Limit = paginated.Limit + 1
+		query.Offset = paginated.Offset
+		if cursor != nil {
+			query.CursorTime = sql.NullTime{Time: cursor.Time, Valid: true}
+			query.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+		}
+		if goingBack {
+			query.Ascending = !query.Ascending
+		}
+		posts, err = postquery.Posts(ctx, apiCfg.DB, query)
+	case goingBack:
+		posts, err = apiCfg.DATABASE.GetNewerPostsForUser(ctx, database.GetNewerPostsForUserParams{
+			UserID:            user.ID,
+			Status:            params.Status,
+			CategoryID:        params.CategoryID,
+			Tag:               params.Tag,
+			CursorPublishedAt: cursor.Time,
+			CursorID:          cursor.ID,
+			Limit:             paginated.Limit + 1,
+		})
+	default:
+		query := database.GetPostsForUserParams{
+			UserID:     user.ID,
+			Status:     params.Status,
+			CategoryID: params.CategoryID,
+			Tag:        params.Tag,
+			Limit:      paginated.Limit + 1,
+			Offset:     paginated.Offset,
+		}
+		if cursor != nil {
+			query.CursorPublishedAt = sql.NullTime{Time: cursor.Time, Valid: true}
+			query.CursorID = uuid.NullUUID{UUID: cursor.ID, Valid: true}
+		}
+		posts, err = apiCfg.DATABASE.GetPostsForUser(ctx, query)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := len(posts) > int(paginated.Limit)
+	if hasMore {
+		posts = posts[:paginated.Limit]
+	}
+	if goingBack {
+		// Posts before the cursor are read in the reverse order, from the cursor.
+		slices.Reverse(posts)
+	}
+	return posts, hasMore, nil
+}
+
+func (apiCfg *ApiConfig) countPosts(ctx context.Context, user database.User, params models.GetPostsParams) (int64, error) {
+	if params.UsesFilterQuery() {
+		return postquery.Count(ctx, apiCfg.DB, params.FilterQuery(user.ID))
+	}
+	return apiCfg.DATABASE.CountPostsForUser(ctx, database.CountPostsForUserParams{
+		UserID:     user.ID,
+		Status:     params.Status,
+		CategoryID: params.CategoryID,
+		Tag:        params.Tag,
+	})
+}
+
+func (apiCfg *ApiConfig) HandleSearchPosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	paginated := models.PaginatedParams{}
+	if err := paginated.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", fmt.Sprintf("Error searching posts: %v", err))
+		return
+	}
+	if err := paginated.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "PAGINATION_ERROR", err.Error())
+		return
+	}
+	params := models.SearchPostsParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_QUERY_PARAMS", err.Error())
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	results, err := apiCfg.DATABASE.SearchPostsForUser(r.Context(), database.SearchPostsForUserParams{
+		Query:  params.TsQuery,
 		UserID: user.ID,
 		Limit:  paginated.Limit,
 		Offset: paginated.Offset,
 	})
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting posts: %v", err))
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error searching posts: %v", err))
 		return
 	}
-	data := models.NewPostsFromDatabase(posts)
-	respondWithJson(w, http.StatusOK, models.Paginated[*models.Post]{
-		Data:   data,
-		Total:  len(data),
-		Offset: int(paginated.Offset),
-		Limit:  int(paginated.Limit),
+	total, err := apiCfg.DATABASE.CountSearchPostsForUser(r.Context(), database.CountSearchPostsForUserParams{
+		Query:  params.TsQuery,
+		UserID: user.ID,
 	})
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error counting posts: %v", err))
+		return
+	}
+	setLinkHeader(w, r, offsetPageLinks(int(total), paginated.Offset, paginated.Limit))
+	respondWithJson(w, http.StatusOK, models.NewPaginated(models.NewSearchResultsFromDatabase(results), total, paginated))
 }
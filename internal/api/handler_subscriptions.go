@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+// HandleCreateSubscription follows the feed at a URL, creating the feed when
+// no feed has the same canonical URL. A page URL is replaced by the feed it
+// advertises.
+func (apiCfg *ApiConfig) HandleCreateSubscription(w http.ResponseWriter, r *http.Request, user database.User) {
+	params := models.CreateSubscriptionParams{}
+	if err := params.Decode(r); err != nil {
+		respondWithError(w, http.StatusBadRequest, "INVALID_REQUEST_BODY", fmt.Sprintf("Error decoding JSON: %v", err))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	_, err := apiCfg.DATABASE.GetFeedByCanonicalUrl(r.Context(), params.CanonicalUrl)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Fetched outside of the transaction, which must not wait on the network.
+		if err := params.Discover(); err != nil {
+			respondWithError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+	} else if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_GET_ERROR", fmt.Sprintf("Error getting feed: %v", err))
+		return
+	}
+	s, err := apiCfg.subscribe(r.Context(), params, user)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "RECORD_CREATE_ERROR", fmt.Sprintf("Error creating subscription: %v", err))
+		return
+	}
+	respondWithJson(w, http.StatusCreated, models.NewSubscriptionFromDatabase(s.feed, s.feedFollow))
+}
+
+type subscription struct {
+	feed          database.Feed
+	feedFollow    database.FeedFollow
+	feedCreated   bool
+	followCreated bool
+}
+
+// subscribe finds or creates the feed of params and follows it in one
+// transaction. The feed URL must already be validated.
+func (apiCfg *ApiConfig) subscribe(ctx context.Context, params models.CreateSubscriptionParams, user database.User) (subscription, error) {
+	s := subscription{}
+	err := apiCfg.withTx(ctx, func(queries *database.Queries) error {
+		var err error
+		s.feed, s.feedCreated, err = findOrCreateFeed(ctx, queries, params, user)
+		if err != nil {
+			return err
+		}
+		feedFollowID := uuid.New()
+		s.feedFollow, err = queries.UpsertFeedFollow(ctx, database.UpsertFeedFollowParams{
+			ID:        feedFollowID,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			FeedID:    s.feed.ID,
+		})
+		s.followCreated = s.feedFollow.ID == feedFollowID
+		return err
+	})
+	return s, err
+}
+
+// findOrCreateFeed returns the feed with the canonical URL of params, creating
+// it when there is none. The insert does nothing when the feed exists, even
+// when a concurrent subscription just created it, and the feed is then read.
+func findOrCreateFeed(ctx context.Context, queries *database.Queries, params models.CreateSubscriptionParams, user database.User) (database.Feed, bool, error) {
+	feed, err := queries.CreateFeedIfNotExists(ctx, database.CreateFeedIfNotExistsParams{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+		Name:         params.Name,
+		Url:          params.Url,
+		UserID:       user.ID,
+		CanonicalUrl: params.CanonicalUrl,
+	})
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, err == nil, err
+	}
+	feed, err = queries.GetFeedByCanonicalUrl(ctx, params.CanonicalUrl)
+	return feed, false, err
+}
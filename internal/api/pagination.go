@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// pageLink is a page of a list, reached by replacing query parameters of the
+// request URL. An empty value removes the parameter.
+type pageLink struct {
+	rel    string
+	params map[string]string
+}
+
+// setLinkHeader sets an RFC 5988 Link header pointing to the given pages.
+func setLinkHeader(w http.ResponseWriter, r *http.Request, links []pageLink) {
+	values := []string{}
+	for _, link := range links {
+		query := r.URL.Query()
+		for key, value := range link.params {
+			if value == "" {
+				query.Del(key)
+			} else {
+				query.Set(key, value)
+			}
+		}
+		u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), link.rel))
+	}
+	if len(values) > 0 {
+		w.Header().Set("Link", strings.Join(values, ", "))
+	}
+}
+
+// offsetPageLinks returns the first, previous, next and last pages of a list
+// paginated with limit and offset.
+func offsetPageLinks(total int, offset int32, limit int32) []pageLink {
+	page := func(rel string, offset int) pageLink {
+		return pageLink{rel: rel, params: map[string]string{
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(int(limit)),
+			"cursor": "",
+		}}
+	}
+	links := []pageLink{page("first", 0)}
+	if offset > 0 {
+		links = append(links, page("prev", max(int(offset-limit), 0)))
+	}
+	if int(offset+limit) < total {
+		links = append(links, page("next", int(offset+limit)))
+	}
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / int(limit) * int(limit)
+	}
+	return append(links, page("last", lastOffset))
+}
+
+// cursorPageLinks returns the previous and next pages of a list paginated
+// with cursors.
+func cursorPageLinks(prevCursor string, nextCursor string) []pageLink {
+	links := []pageLink{}
+	if prevCursor != "" {
+		links = append(links, pageLink{rel: "prev", params: map[string]string{"cursor": prevCursor, "offset": ""}})
+	}
+	if nextCursor != "" {
+		links = append(links, pageLink{rel: "next", params: map[string]string{"cursor": nextCursor, "offset": ""}})
+	}
+	return links
+}
@@ -7,15 +7,34 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countFeedsFollowedByUser = `-- name: CountFeedsFollowedByUser :one
+SELECT COUNT(*) FROM feed_follows
+WHERE user_id = $1
+    AND ($2::uuid IS NULL OR category_id = $2::uuid)
+`
+
+type CountFeedsFollowedByUserParams struct {
+	UserID     uuid.UUID
+	CategoryID uuid.NullUUID
+}
+
+func (q *Queries) CountFeedsFollowedByUser(ctx context.Context, arg CountFeedsFollowedByUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedsFollowedByUser, arg.UserID, arg.CategoryID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeedFollow = `-- name: CreateFeedFollow :one
 INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline
 `
 
 type CreateFeedFollowParams struct {
@@ -41,6 +60,11 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.CategoryID,
+		&i.CustomTitle,
+		&i.Notes,
+		&i.Priority,
+		&i.HideFromTimeline,
 	)
 	return i, err
 }
@@ -59,12 +83,99 @@ func (q *Queries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowPara
 	return err
 }
 
+const getFeedFollow = `-- name: GetFeedFollow :one
+SELECT id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline FROM feed_follows WHERE id = $1 AND user_id = $2
+`
+
+type GetFeedFollowParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, arg.ID, arg.UserID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.CustomTitle,
+		&i.Notes,
+		&i.Priority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
+
+const getFeedFollowsForExport = `-- name: GetFeedFollowsForExport :many
+SELECT f.name, f.url, ff.custom_title, c.name AS category_name
+FROM feed_follows ff
+JOIN feeds f ON f.id = ff.feed_id
+LEFT JOIN categories c ON c.id = ff.category_id
+WHERE ff.user_id = $1
+ORDER BY c.name NULLS FIRST, ff.priority DESC, f.name
+`
+
+type GetFeedFollowsForExportRow struct {
+	Name         string
+	Url          string
+	CustomTitle  sql.NullString
+	CategoryName sql.NullString
+}
+
+func (q *Queries) GetFeedFollowsForExport(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForExport, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForExportRow
+	for rows.Next() {
+		var i GetFeedFollowsForExportRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Url,
+			&i.CustomTitle,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeedsFollowedByUser = `-- name: GetFeedsFollowedByUser :many
-SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE user_id = $1
+SELECT id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline FROM feed_follows
+WHERE user_id = $1
+    AND ($2::uuid IS NULL OR category_id = $2::uuid)
+ORDER BY priority DESC, created_at DESC, id
+LIMIT $3 OFFSET $4
 `
 
-func (q *Queries) GetFeedsFollowedByUser(ctx context.Context, userID uuid.UUID) ([]FeedFollow, error) {
-	rows, err := q.db.QueryContext(ctx, getFeedsFollowedByUser, userID)
+type GetFeedsFollowedByUserParams struct {
+	UserID     uuid.UUID
+	CategoryID uuid.NullUUID
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) GetFeedsFollowedByUser(ctx context.Context, arg GetFeedsFollowedByUserParams) ([]FeedFollow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsFollowedByUser,
+		arg.UserID,
+		arg.CategoryID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +189,11 @@ func (q *Queries) GetFeedsFollowedByUser(ctx context.Context, userID uuid.UUID)
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.CategoryID,
+			&i.CustomTitle,
+			&i.Notes,
+			&i.Priority,
+			&i.HideFromTimeline,
 		); err != nil {
 			return nil, err
 		}
@@ -91,3 +207,140 @@ func (q *Queries) GetFeedsFollowedByUser(ctx context.Context, userID uuid.UUID)
 	}
 	return items, nil
 }
+
+const isFollowingFeed = `-- name: IsFollowingFeed :one
+SELECT EXISTS (
+    SELECT 1 FROM feed_follows WHERE user_id = $1 AND feed_id = $2
+)
+`
+
+type IsFollowingFeedParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) IsFollowingFeed(ctx context.Context, arg IsFollowingFeedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isFollowingFeed, arg.UserID, arg.FeedID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const setFeedFollowCategory = `-- name: SetFeedFollowCategory :one
+UPDATE feed_follows
+SET category_id = $1, updated_at = NOW()
+WHERE id = $2
+    AND user_id = $3
+    AND (
+        $1::uuid IS NULL
+        OR EXISTS (
+            SELECT 1 FROM categories c
+            WHERE c.id = $1::uuid AND c.user_id = $3
+        )
+    )
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline
+`
+
+type SetFeedFollowCategoryParams struct {
+	CategoryID uuid.NullUUID
+	ID         uuid.UUID
+	UserID     uuid.UUID
+}
+
+func (q *Queries) SetFeedFollowCategory(ctx context.Context, arg SetFeedFollowCategoryParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, setFeedFollowCategory, arg.CategoryID, arg.ID, arg.UserID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.CustomTitle,
+		&i.Notes,
+		&i.Priority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
+
+const updateFeedFollow = `-- name: UpdateFeedFollow :one
+UPDATE feed_follows
+SET custom_title = $3, notes = $4, priority = $5, hide_from_timeline = $6, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline
+`
+
+type UpdateFeedFollowParams struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	CustomTitle      sql.NullString
+	Notes            sql.NullString
+	Priority         int32
+	HideFromTimeline bool
+}
+
+func (q *Queries) UpdateFeedFollow(ctx context.Context, arg UpdateFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedFollow,
+		arg.ID,
+		arg.UserID,
+		arg.CustomTitle,
+		arg.Notes,
+		arg.Priority,
+		arg.HideFromTimeline,
+	)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.CustomTitle,
+		&i.Notes,
+		&i.Priority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
+
+const upsertFeedFollow = `-- name: UpsertFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, feed_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, custom_title, notes, priority, hide_from_timeline
+`
+
+type UpsertFeedFollowParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+func (q *Queries) UpsertFeedFollow(ctx context.Context, arg UpsertFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, upsertFeedFollow,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedID,
+	)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.CustomTitle,
+		&i.Notes,
+		&i.Priority,
+		&i.HideFromTimeline,
+	)
+	return i, err
+}
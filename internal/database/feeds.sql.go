@@ -7,24 +7,94 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countFeeds = `-- name: CountFeeds :one
+SELECT COUNT(*)
+FROM feeds f
+WHERE (
+        $1::text IS NULL
+        OR f.name ILIKE '%' || $1::text || '%'
+        OR f.url ILIKE '%' || $1::text || '%'
+        OR f.description ILIKE '%' || $1::text || '%'
+    )
+    AND ($2::text IS NULL OR lower(f.language) LIKE lower($2::text) || '%')
+`
+
+type CountFeedsParams struct {
+	Query    sql.NullString
+	Language sql.NullString
+}
+
+func (q *Queries) CountFeeds(ctx context.Context, arg CountFeedsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeeds, arg.Query, arg.Language)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOtherFeedUsers = `-- name: CountOtherFeedUsers :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = $1 AND ff.user_id <> $2) AS followers,
+    (
+        SELECT COUNT(DISTINCT saved.user_id)
+        FROM (
+            SELECT ps.user_id
+            FROM post_stars ps
+            JOIN posts p ON p.id = ps.post_id
+            WHERE p.feed_id = $1
+            UNION ALL
+            SELECT c.user_id
+            FROM collection_posts cp
+            JOIN collections c ON c.id = cp.collection_id
+            JOIN posts p ON p.id = cp.post_id
+            WHERE p.feed_id = $1
+        ) saved
+        WHERE saved.user_id <> $2
+    ) AS saving_users
+`
+
+type CountOtherFeedUsersParams struct {
+	FeedID uuid.UUID
+	UserID uuid.UUID
+}
+
+type CountOtherFeedUsersRow struct {
+	Followers   int64
+	SavingUsers int64
+}
+
+// CountOtherFeedUsers counts the users other than the given one following the
+// feed, and those who starred or collected its posts, lost with the feed.
+func (q *Queries) CountOtherFeedUsers(ctx context.Context, arg CountOtherFeedUsersParams) (CountOtherFeedUsersRow, error) {
+	row := q.db.QueryRowContext(ctx, countOtherFeedUsers, arg.FeedID, arg.UserID)
+	var i CountOtherFeedUsersRow
+	err := row.Scan(
+		&i.Followers,
+		&i.SavingUsers,
+	)
+	return i, err
+}
+
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, fetch_full_content, canonical_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error
 `
 
 type CreateFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	FetchFullContent bool
+	CanonicalUrl     string
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -35,6 +105,8 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.FetchFullContent,
+		arg.CanonicalUrl,
 	)
 	var i Feed
 	err := row.Scan(
@@ -45,31 +117,136 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
 	)
 	return i, err
 }
 
+const createFeedIfNotExists = `-- name: CreateFeedIfNotExists :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, fetch_full_content, canonical_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (canonical_url) DO NOTHING
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error
+`
+
+type CreateFeedIfNotExistsParams struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	FetchFullContent bool
+	CanonicalUrl     string
+}
+
+func (q *Queries) CreateFeedIfNotExists(ctx context.Context, arg CreateFeedIfNotExistsParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, createFeedIfNotExists,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Url,
+		arg.UserID,
+		arg.FetchFullContent,
+		arg.CanonicalUrl,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
 const getAllFeeds = `-- name: GetAllFeeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at 
-FROM feeds 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2
+SELECT f.id, f.created_at, f.updated_at, f.name, f.url, f.user_id, f.last_fetched_at, f.fetch_full_content, f.canonical_url, f.last_warning, f.description, f.language, f.last_error, stats.follower_count, stats.last_post_at
+FROM feeds f
+CROSS JOIN LATERAL (
+    SELECT
+        (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = f.id) AS follower_count,
+        (SELECT MAX(p.published_at) FROM posts p WHERE p.feed_id = f.id) AS last_post_at
+) stats
+WHERE (
+        $1::text IS NULL
+        OR f.name ILIKE '%' || $1::text || '%'
+        OR f.url ILIKE '%' || $1::text || '%'
+        OR f.description ILIKE '%' || $1::text || '%'
+    )
+    AND ($2::text IS NULL OR lower(f.language) LIKE lower($2::text) || '%')
+ORDER BY
+    CASE WHEN $3::text = 'followers' THEN stats.follower_count END DESC,
+    CASE WHEN $3::text = 'active' THEN stats.last_post_at END DESC NULLS LAST,
+    f.created_at DESC
+LIMIT $4 OFFSET $5
 `
 
 type GetAllFeedsParams struct {
-	Limit  int32
-	Offset int32
+	Query    sql.NullString
+	Language sql.NullString
+	Sort     string
+	Limit    int32
+	Offset   int32
+}
+
+type GetAllFeedsRow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	FetchFullContent bool
+	CanonicalUrl     string
+	LastWarning      sql.NullString
+	Description      sql.NullString
+	Language         sql.NullString
+	LastError        sql.NullString
+	FollowerCount    int64
+	LastPostAt       sql.NullTime
 }
 
-func (q *Queries) GetAllFeeds(ctx context.Context, arg GetAllFeedsParams) ([]Feed, error) {
-	rows, err := q.db.QueryContext(ctx, getAllFeeds, arg.Limit, arg.Offset)
+func (q *Queries) GetAllFeeds(ctx context.Context, arg GetAllFeedsParams) ([]GetAllFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllFeeds,
+		arg.Query,
+		arg.Language,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Feed
+	var items []GetAllFeedsRow
 	for rows.Next() {
-		var i Feed
+		var i GetAllFeedsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -78,6 +255,14 @@ func (q *Queries) GetAllFeeds(ctx context.Context, arg GetAllFeedsParams) ([]Fee
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			&i.CanonicalUrl,
+			&i.LastWarning,
+			&i.Description,
+			&i.Language,
+			&i.LastError,
+			&i.FollowerCount,
+			&i.LastPostAt,
 		); err != nil {
 			return nil, err
 		}
@@ -92,8 +277,90 @@ func (q *Queries) GetAllFeeds(ctx context.Context, arg GetAllFeedsParams) ([]Fee
 	return items, nil
 }
 
+const getFeed = `-- name: GetFeed :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error FROM feeds WHERE id = $1
+`
+
+func (q *Queries) GetFeed(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeed, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const getFeedByCanonicalUrl = `-- name: GetFeedByCanonicalUrl :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error FROM feeds WHERE canonical_url = $1
+`
+
+func (q *Queries) GetFeedByCanonicalUrl(ctx context.Context, canonicalUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByCanonicalUrl, canonicalUrl)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const getFeedStats = `-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = $1) AS follower_count,
+    (SELECT COUNT(*) FROM posts p WHERE p.feed_id = $1) AS post_count,
+    (SELECT COUNT(*) FROM posts p WHERE p.feed_id = $1 AND p.published_at >= $2::timestamptz) AS recent_post_count,
+    (SELECT MAX(p.published_at) FROM posts p WHERE p.feed_id = $1) AS last_post_at
+`
+
+type GetFeedStatsParams struct {
+	FeedID      uuid.UUID
+	RecentSince time.Time
+}
+
+type GetFeedStatsRow struct {
+	FollowerCount   int64
+	PostCount       int64
+	RecentPostCount int64
+	LastPostAt      sql.NullTime
+}
+
+func (q *Queries) GetFeedStats(ctx context.Context, arg GetFeedStatsParams) (GetFeedStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedStats, arg.FeedID, arg.RecentSince)
+	var i GetFeedStatsRow
+	err := row.Scan(
+		&i.FollowerCount,
+		&i.PostCount,
+		&i.RecentPostCount,
+		&i.LastPostAt,
+	)
+	return i, err
+}
+
 const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at 
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error 
 FROM feeds 
 ORDER BY last_fetched_at ASC NULLS FIRST 
 LIMIT $1
@@ -116,6 +383,12 @@ func (q *Queries) GetNextFeedsToFetch(ctx context.Context, limit int32) ([]Feed,
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			&i.CanonicalUrl,
+			&i.LastWarning,
+			&i.Description,
+			&i.Language,
+			&i.LastError,
 		); err != nil {
 			return nil, err
 		}
@@ -134,7 +407,7 @@ const markFeedAsFetched = `-- name: MarkFeedAsFetched :one
 UPDATE feeds 
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error
 `
 
 func (q *Queries) MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -148,6 +421,108 @@ func (q *Queries) MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (Feed, er
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const setFeedError = `-- name: SetFeedError :exec
+UPDATE feeds
+SET last_error = $2
+WHERE id = $1
+`
+
+type SetFeedErrorParams struct {
+	ID        uuid.UUID
+	LastError sql.NullString
+}
+
+func (q *Queries) SetFeedError(ctx context.Context, arg SetFeedErrorParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedError, arg.ID, arg.LastError)
+	return err
+}
+
+const setFeedWarning = `-- name: SetFeedWarning :exec
+UPDATE feeds
+SET last_warning = $2
+WHERE id = $1
+`
+
+type SetFeedWarningParams struct {
+	ID          uuid.UUID
+	LastWarning sql.NullString
+}
+
+func (q *Queries) SetFeedWarning(ctx context.Context, arg SetFeedWarningParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedWarning, arg.ID, arg.LastWarning)
+	return err
+}
+
+const updateFeed = `-- name: UpdateFeed :one
+UPDATE feeds
+SET name = $2,
+    url = $3,
+    canonical_url = $4,
+    fetch_full_content = $5,
+    last_fetched_at = CASE WHEN url = $3 THEN last_fetched_at ELSE NULL END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, canonical_url, last_warning, description, language, last_error
+`
+
+type UpdateFeedParams struct {
+	ID               uuid.UUID
+	Name             string
+	Url              string
+	CanonicalUrl     string
+	FetchFullContent bool
+}
+
+func (q *Queries) UpdateFeed(ctx context.Context, arg UpdateFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeed,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.CanonicalUrl,
+		arg.FetchFullContent,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.CanonicalUrl,
+		&i.LastWarning,
+		&i.Description,
+		&i.Language,
+		&i.LastError,
 	)
 	return i, err
 }
+
+const updateFeedMetadata = `-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET description = $2, language = $3
+WHERE id = $1
+`
+
+type UpdateFeedMetadataParams struct {
+	ID          uuid.UUID
+	Description sql.NullString
+	Language    sql.NullString
+}
+
+func (q *Queries) UpdateFeedMetadata(ctx context.Context, arg UpdateFeedMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedMetadata, arg.ID, arg.Description, arg.Language)
+	return err
+}
@@ -11,33 +11,177 @@ import (
 	"github.com/google/uuid"
 )
 
-type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
-}
-
-type FeedFollow struct {
+type Category struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	Name      string
 }
 
-type Post struct {
+type Collection struct {
 	ID          uuid.UUID
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	UserID      uuid.UUID
 	Title       string
-	Url         string
 	Description sql.NullString
-	PublishedAt time.Time
+}
+
+type CollectionPost struct {
+	CollectionID uuid.UUID
+	PostID       uuid.UUID
+	AddedAt      time.Time
+}
+
+type Feed struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	FetchFullContent bool
+	CanonicalUrl     string
+	LastWarning      sql.NullString
+	Description      sql.NullString
+	Language         sql.NullString
+	LastError        sql.NullString
+}
+
+type FeedFollow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	CategoryID       uuid.NullUUID
+	CustomTitle      sql.NullString
+	Notes            sql.NullString
+	Priority         int32
+	HideFromTimeline bool
+}
+
+type FeedIcon struct {
 	FeedID      uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	SourceUrl   string
+	ContentType string
+	Data        []byte
+	Etag        string
+}
+
+type FeedIconFailure struct {
+	FeedID   uuid.UUID
+	Failures int32
+	RetryAt  time.Time
+}
+
+type FeedToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	Kind       string
+	CategoryID uuid.NullUUID
+	Tag        sql.NullString
+	Token      string
+}
+
+type FilterRule struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Field     string
+	MatchType string
+	Pattern   string
+	Actions   []string
+	Tag       sql.NullString
+}
+
+type FilterRuleRun struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	FilterRuleID uuid.UUID
+	UserID       uuid.UUID
+	Status       string
+	Matched      int64
+	Error        sql.NullString
+}
+
+type Mute struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Kind      string
+	Value     string
+}
+
+type OpmlImport struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Status    string
+	Total     int32
+	Error     sql.NullString
+}
+
+type OpmlImportResult struct {
+	ID       uuid.UUID
+	ImportID uuid.UUID
+	Position int32
+	Title    string
+	Url      string
+	Category sql.NullString
+	Status   string
+	Error    sql.NullString
+}
+
+type Post struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   sql.NullString
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Content       sql.NullString
+	EnclosureUrl  sql.NullString
+	EnclosureType sql.NullString
+	CanonicalUrl  string
+	Truncated     bool
+	Author        sql.NullString
+}
+
+type PostHide struct {
+	UserID   uuid.UUID
+	PostID   uuid.UUID
+	HiddenAt time.Time
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
+}
+
+type PostTag struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Tag       string
+	CreatedAt time.Time
 }
 
 type User struct {
@@ -46,4 +190,5 @@ type User struct {
 	UpdatedAt time.Time
 	Name      string
 	ApiKey    string
+	IsAdmin   bool
 }
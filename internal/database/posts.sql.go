@@ -13,6 +13,82 @@ import (
 	"github.com/google/uuid"
 )
 
+const countPostsForFeed = `-- name: CountPostsForFeed :one
+SELECT COUNT(*) FROM posts WHERE feed_id = $1
+`
+
+func (q *Queries) CountPostsForFeed(ctx context.Context, feedID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsForFeed, feedID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPostsForUser = `-- name: CountPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
+WHERE ff.user_id = $1
+    AND (
+        $2::text = 'all'
+        OR ($2::text = 'read' AND pr.post_id IS NOT NULL)
+        OR ($2::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND ($3::uuid IS NULL OR ff.category_id = $3::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        $4::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = $4::text
+        )
+    )
+    AND post_visible_to_user(p, ff.user_id)
+`
+
+type CountPostsForUserParams struct {
+	UserID     uuid.UUID
+	Status     string
+	CategoryID uuid.NullUUID
+	Tag        sql.NullString
+}
+
+func (q *Queries) CountPostsForUser(ctx context.Context, arg CountPostsForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPostsForUser,
+		arg.UserID,
+		arg.Status,
+		arg.CategoryID,
+		arg.Tag,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPostsForUser = `-- name: CountSearchPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id,
+    to_tsquery('english', $1::text) query
+WHERE ff.user_id = $2
+    AND NOT ff.hide_from_timeline
+    AND post_visible_to_user(p, ff.user_id)
+    AND posts_search_vector(title, description, content) @@ query
+`
+
+type CountSearchPostsForUserParams struct {
+	Query  string
+	UserID uuid.UUID
+}
+
+func (q *Queries) CountSearchPostsForUser(ctx context.Context, arg CountSearchPostsForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchPostsForUser, arg.Query, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
     id,
@@ -22,21 +98,31 @@ INSERT INTO posts (
     url,
     description,
     published_at,
-    feed_id
+    feed_id,
+    enclosure_url,
+    enclosure_type,
+    canonical_url,
+    truncated,
+    author
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, content, enclosure_url, enclosure_type, canonical_url, truncated, author
 `
 
 type CreatePostParams struct {
-	ID          uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Title       string
-	Url         string
-	Description sql.NullString
-	PublishedAt time.Time
-	FeedID      uuid.UUID
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   sql.NullString
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	EnclosureUrl  sql.NullString
+	EnclosureType sql.NullString
+	CanonicalUrl  string
+	Truncated     bool
+	Author        sql.NullString
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -49,6 +135,11 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.EnclosureUrl,
+		arg.EnclosureType,
+		arg.CanonicalUrl,
+		arg.Truncated,
+		arg.Author,
 	)
 	var i Post
 	err := row.Scan(
@@ -60,28 +151,262 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Content,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.CanonicalUrl,
+		&i.Truncated,
+		&i.Author,
 	)
 	return i, err
 }
 
-const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id
+const getNewerPostsForUser = `-- name: GetNewerPostsForUser :many
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.enclosure_url, p.enclosure_type, p.canonical_url, p.truncated, p.author
 FROM posts p
 JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
 WHERE ff.user_id = $1
-ORDER BY p.published_at DESC
+    AND (
+        $2::text = 'all'
+        OR ($2::text = 'read' AND pr.post_id IS NOT NULL)
+        OR ($2::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND ($3::uuid IS NULL OR ff.category_id = $3::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        $4::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = $4::text
+        )
+    )
+    AND (p.published_at, p.id) > ($5::timestamptz, $6::uuid)
+    AND post_visible_to_user(p, ff.user_id)
+ORDER BY p.published_at ASC, p.id ASC
+LIMIT $7
+`
+
+type GetNewerPostsForUserParams struct {
+	UserID            uuid.UUID
+	Status            string
+	CategoryID        uuid.NullUUID
+	Tag               sql.NullString
+	CursorPublishedAt time.Time
+	CursorID          uuid.UUID
+	Limit             int32
+}
+
+func (q *Queries) GetNewerPostsForUser(ctx context.Context, arg GetNewerPostsForUserParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getNewerPostsForUser,
+		arg.UserID,
+		arg.Status,
+		arg.CategoryID,
+		arg.Tag,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.CanonicalUrl,
+			&i.Truncated,
+			&i.Author,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPost = `-- name: GetPost :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, enclosure_url, enclosure_type, canonical_url, truncated, author FROM posts WHERE id = $1
+`
+
+func (q *Queries) GetPost(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.CanonicalUrl,
+		&i.Truncated,
+		&i.Author,
+	)
+	return i, err
+}
+
+const getPostForUser = `-- name: GetPostForUser :one
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.enclosure_url, p.enclosure_type, p.canonical_url, p.truncated, p.author
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE p.id = $1 AND ff.user_id = $2
+`
+
+type GetPostForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetPostForUser(ctx context.Context, arg GetPostForUserParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostForUser, arg.ID, arg.UserID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.CanonicalUrl,
+		&i.Truncated,
+		&i.Author,
+	)
+	return i, err
+}
+
+const getPostsForFeed = `-- name: GetPostsForFeed :many
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, enclosure_url, enclosure_type, canonical_url, truncated, author FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC, id DESC
 LIMIT $2
 OFFSET $3
 `
 
-type GetPostsForUserParams struct {
-	UserID uuid.UUID
+type GetPostsForFeedParams struct {
+	FeedID uuid.UUID
 	Limit  int32
 	Offset int32
 }
 
+func (q *Queries) GetPostsForFeed(ctx context.Context, arg GetPostsForFeedParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForFeed, arg.FeedID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.CanonicalUrl,
+			&i.Truncated,
+			&i.Author,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.enclosure_url, p.enclosure_type, p.canonical_url, p.truncated, p.author
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
+WHERE ff.user_id = $1
+    AND (
+        $2::text = 'all'
+        OR ($2::text = 'read' AND pr.post_id IS NOT NULL)
+        OR ($2::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND ($3::uuid IS NULL OR ff.category_id = $3::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        $4::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = $4::text
+        )
+    )
+    AND (
+        $5::timestamptz IS NULL
+        OR (p.published_at, p.id) < ($5::timestamptz, $6::uuid)
+    )
+    AND post_visible_to_user(p, ff.user_id)
+ORDER BY p.published_at DESC, p.id DESC
+LIMIT $7
+OFFSET $8
+`
+
+type GetPostsForUserParams struct {
+	UserID            uuid.UUID
+	Status            string
+	CategoryID        uuid.NullUUID
+	Tag               sql.NullString
+	CursorPublishedAt sql.NullTime
+	CursorID          uuid.NullUUID
+	Limit             int32
+	Offset            int32
+}
+
 func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.Status,
+		arg.CategoryID,
+		arg.Tag,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +423,12 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.CanonicalUrl,
+			&i.Truncated,
+			&i.Author,
 		); err != nil {
 			return nil, err
 		}
@@ -111,3 +442,111 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const searchPostsForUser = `-- name: SearchPostsForUser :many
+SELECT p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.enclosure_url, p.enclosure_type, p.canonical_url, p.truncated, p.author,
+    ts_rank(posts_search_vector(title, description, content), query) AS rank,
+    ts_headline(
+        'english',
+        regexp_replace(concat_ws(' ', p.title, p.description, p.content), '<[^>]*>', ' ', 'g'),
+        query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'
+    ) AS snippet
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id,
+    to_tsquery('english', $1::text) query
+WHERE ff.user_id = $2
+    AND NOT ff.hide_from_timeline
+    AND post_visible_to_user(p, ff.user_id)
+    AND posts_search_vector(title, description, content) @@ query
+ORDER BY rank DESC, p.published_at DESC
+LIMIT $3
+OFFSET $4
+`
+
+type SearchPostsForUserParams struct {
+	Query  string
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+type SearchPostsForUserRow struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   sql.NullString
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Content       sql.NullString
+	EnclosureUrl  sql.NullString
+	EnclosureType sql.NullString
+	CanonicalUrl  string
+	Truncated     bool
+	Author        sql.NullString
+	Rank          float32
+	Snippet       string
+}
+
+func (q *Queries) SearchPostsForUser(ctx context.Context, arg SearchPostsForUserParams) ([]SearchPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPostsForUser,
+		arg.Query,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsForUserRow
+	for rows.Next() {
+		var i SearchPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.CanonicalUrl,
+			&i.Truncated,
+			&i.Author,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePostContent = `-- name: UpdatePostContent :exec
+UPDATE posts
+SET content = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdatePostContentParams struct {
+	ID      uuid.UUID
+	Content sql.NullString
+}
+
+func (q *Queries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostContent, arg.ID, arg.Content)
+	return err
+}
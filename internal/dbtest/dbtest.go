@@ -0,0 +1,99 @@
+// Package dbtest gives the tests that need PostgreSQL a database of their own.
+// They run against the server of TEST_DATABASE_URL and are skipped when it is
+// not set. Each test gets a new schema, dropped when the test ends, so tests
+// never see each other's rows nor the tables of the database itself.
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// Open returns a connection pool to an empty schema, set as the search path
+// of every connection.
+func Open(t *testing.T) *sql.DB {
+	t.Helper()
+	databaseUrl := os.Getenv("TEST_DATABASE_URL")
+	if databaseUrl == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	admin, err := sql.Open("postgres", databaseUrl)
+	if err != nil {
+		t.Fatalf("Error opening test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("Error creating test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("Error dropping test schema: %v", err)
+		}
+	})
+	db, err := sql.Open("postgres", withSearchPath(databaseUrl, schema))
+	if err != nil {
+		t.Fatalf("Error opening test schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Migrate returns a connection pool to a schema with every migration applied.
+func Migrate(t *testing.T) *sql.DB {
+	t.Helper()
+	db := Open(t)
+	files, err := filepath.Glob(filepath.Join(SchemaDir(), "*.sql"))
+	if err != nil {
+		t.Fatalf("Error listing migrations: %v", err)
+	}
+	for _, file := range files {
+		up, err := UpMigration(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("Error applying migration %v: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// SchemaDir returns the directory of the migrations.
+func SchemaDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "sql", "schema")
+}
+
+// UpMigration returns the statements of the Up section of a goose migration,
+// sent at once since the driver accepts several statements without arguments.
+func UpMigration(file string) (string, error) {
+	dat, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	_, up, ok := strings.Cut(string(dat), "-- +goose Up")
+	if !ok {
+		return "", fmt.Errorf("migration %v has no Up section", filepath.Base(file))
+	}
+	up, _, _ = strings.Cut(up, "-- +goose Down")
+	return up, nil
+}
+
+func withSearchPath(databaseUrl string, schema string) string {
+	if !strings.HasPrefix(databaseUrl, "postgres://") && !strings.HasPrefix(databaseUrl, "postgresql://") {
+		return databaseUrl + " search_path=" + schema
+	}
+	if strings.Contains(databaseUrl, "?") {
+		return databaseUrl + "&search_path=" + schema
+	}
+	return databaseUrl + "?search_path=" + schema
+}
@@ -0,0 +1,270 @@
+// Package extractor finds the main content of an HTML article and returns it
+// as sanitized HTML.
+package extractor
+
+import (
+	"encoding/xml"
+	"errors"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// unsafeBlocks matches the elements whose raw text would confuse the
+// tokenizer, together with comments.
+var unsafeBlocks = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>|<noscript\b.*?</noscript\s*>|<!--.*?-->`)
+
+// unlikelyCandidates matches class and id values of boilerplate containers.
+var unlikelyCandidates = regexp.MustCompile(`(?i)comment|sidebar|footer|header|nav|menu|share|social|related|promo|advert|banner|cookie|popup|subscribe`)
+
+// droppedTags are removed together with their children.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "nav": true, "aside": true,
+	"footer": true, "header": true, "form": true, "button": true, "input": true,
+	"select": true, "textarea": true, "iframe": true, "object": true, "embed": true,
+	"svg": true, "canvas": true, "template": true, "head": true,
+}
+
+// allowedTags are kept in the output, any other tag is unwrapped.
+var allowedTags = map[string]bool{
+	"p": true, "br": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "blockquote": true, "pre": true, "code": true,
+	"em": true, "i": true, "strong": true, "b": true, "a": true, "img": true,
+	"figure": true, "figcaption": true, "table": true, "thead": true, "tbody": true,
+	"tr": true, "th": true, "td": true, "hr": true,
+}
+
+// voidTags are the HTML void elements, which never have children nor an end
+// tag. RawToken does not close them, so they must not become the current node.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "param": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// closesParagraph lists the tags whose start implicitly ends an open paragraph.
+var closesParagraph = map[string]bool{
+	"p": true, "div": true, "ul": true, "ol": true, "table": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "figure": true, "hr": true,
+}
+
+// closesSibling lists the tags whose start implicitly ends an open sibling of the same tag.
+var closesSibling = map[string]bool{"li": true, "tr": true, "td": true, "th": true, "dt": true, "dd": true}
+
+type node struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	parent   *node
+	children []*node
+	score    float64
+}
+
+// Extract parses an HTML document and returns its main content as sanitized
+// HTML of at most maxLength bytes. Relative links are resolved against base.
+func Extract(r io.Reader, base *url.URL, maxLength int) (string, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	root := parse(unsafeBlocks.ReplaceAllString(string(raw), ""))
+	candidate := findCandidate(root)
+	if candidate == nil {
+		return "", errors.New("no content found")
+	}
+	out := &renderer{base: base, maxLength: maxLength}
+	out.render(candidate)
+	content := strings.TrimSpace(out.String())
+	if content == "" {
+		return "", errors.New("no content found")
+	}
+	return content, nil
+}
+
+func parse(document string) *node {
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+	root := &node{tag: "#root"}
+	current := root
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			// Malformed markup ends the parsing, keeping what was read so far.
+			return root
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			tag := strings.ToLower(t.Name.Local)
+			if (current.tag == "p" && closesParagraph[tag]) || (current.tag == tag && closesSibling[tag]) {
+				current = current.parent
+			}
+			n := &node{tag: tag, attrs: map[string]string{}, parent: current}
+			for _, attr := range t.Attr {
+				n.attrs[strings.ToLower(attr.Name.Local)] = attr.Value
+			}
+			current.children = append(current.children, n)
+			if !voidTags[n.tag] {
+				current = n
+			}
+		case xml.EndElement:
+			tag := strings.ToLower(t.Name.Local)
+			for n := current; n != root; n = n.parent {
+				if n.tag == tag {
+					current = n.parent
+					break
+				}
+			}
+		case xml.CharData:
+			current.children = append(current.children, &node{text: string(t), parent: current})
+		}
+	}
+}
+
+// findCandidate scores every paragraph's text on its parent and grandparent and
+// returns the container with the best score.
+func findCandidate(root *node) *node {
+	var best *node
+	var visit func(n *node)
+	visit = func(n *node) {
+		if droppedTags[n.tag] || isUnlikely(n) {
+			return
+		}
+		if n.tag == "p" || n.tag == "pre" || n.tag == "blockquote" {
+			length := len(strings.TrimSpace(textContent(n)))
+			if length >= 25 {
+				score := 1 + float64(length)/100 + float64(strings.Count(textContent(n), ","))
+				if n.parent != nil {
+					n.parent.score += score
+					if n.parent.parent != nil {
+						n.parent.parent.score += score / 2
+					}
+				}
+			}
+		}
+		for _, child := range n.children {
+			visit(child)
+		}
+		if n.tag == "article" || n.tag == "main" {
+			n.score += 10
+		}
+		if n.tag != "" && n != root && (best == nil || n.score > best.score) {
+			best = n
+		}
+	}
+	visit(root)
+	if best == nil || best.score == 0 {
+		return nil
+	}
+	return best
+}
+
+func isUnlikely(n *node) bool {
+	if n.tag == "" || n.tag == "body" || n.tag == "article" || n.tag == "main" {
+		return false
+	}
+	return unlikelyCandidates.MatchString(n.attrs["class"] + " " + n.attrs["id"])
+}
+
+func textContent(n *node) string {
+	if n.tag == "" {
+		return n.text
+	}
+	var sb strings.Builder
+	for _, child := range n.children {
+		sb.WriteString(textContent(child))
+	}
+	return sb.String()
+}
+
+type renderer struct {
+	strings.Builder
+	base      *url.URL
+	maxLength int
+	full      bool
+}
+
+func (r *renderer) write(s string) bool {
+	if r.full || r.Len()+len(s) > r.maxLength {
+		r.full = true
+		return false
+	}
+	r.WriteString(s)
+	return true
+}
+
+func (r *renderer) render(n *node) {
+	if r.full {
+		return
+	}
+	if n.tag == "" {
+		r.write(html.EscapeString(n.text))
+		return
+	}
+	if droppedTags[n.tag] || isUnlikely(n) {
+		return
+	}
+	if !allowedTags[n.tag] {
+		for _, child := range n.children {
+			r.render(child)
+		}
+		return
+	}
+	open := "<" + n.tag + r.attributes(n) + ">"
+	closing := "</" + n.tag + ">"
+	if voidTags[n.tag] {
+		r.write(open)
+		return
+	}
+	// Reserve room for the closing tag so truncated output stays well formed.
+	r.maxLength -= len(closing)
+	if !r.write(open) {
+		r.maxLength += len(closing)
+		return
+	}
+	for _, child := range n.children {
+		r.render(child)
+	}
+	r.maxLength += len(closing)
+	r.WriteString(closing)
+}
+
+func (r *renderer) attributes(n *node) string {
+	var attrs strings.Builder
+	switch n.tag {
+	case "a":
+		if href, ok := r.resolve(n.attrs["href"]); ok {
+			attrs.WriteString(` href="` + html.EscapeString(href) + `"`)
+		}
+	case "img":
+		if src, ok := r.resolve(n.attrs["src"]); ok {
+			attrs.WriteString(` src="` + html.EscapeString(src) + `"`)
+		}
+		if alt := n.attrs["alt"]; alt != "" {
+			attrs.WriteString(` alt="` + html.EscapeString(alt) + `"`)
+		}
+	}
+	return attrs.String()
+}
+
+// resolve returns the absolute form of an http or https link.
+func (r *renderer) resolve(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", false
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+	if r.base != nil {
+		u = r.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+	return u.String(), true
+}
@@ -0,0 +1,94 @@
+package extractor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const paragraph = "This paragraph is long enough, with a few commas, to be scored as content."
+
+func TestExtract(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1")
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name:     "keeps the article and drops the boilerplate",
+			document: `<html><body><nav><p>` + paragraph + `</p></nav><article><p>` + paragraph + `</p></article><footer>Footer</footer></body></html>`,
+			want:     `<p>` + paragraph + `</p>`,
+		},
+		{
+			name:     "removes scripts and styles",
+			document: `<article><script>alert(1)</script><style>p {}</style><p>` + paragraph + `</p></article>`,
+			want:     `<p>` + paragraph + `</p>`,
+		},
+		{
+			name:     "unwraps unknown tags and drops attributes",
+			document: `<article><p class="lead" onclick="x()"><span>` + paragraph + `</span></p></article>`,
+			want:     `<p>` + paragraph + `</p>`,
+		},
+		{
+			name:     "resolves links and drops unsafe ones",
+			document: `<article><p>` + paragraph + ` <a href="../2">next</a> <a href="javascript:alert(1)">bad</a></p></article>`,
+			want:     `<p>` + paragraph + ` <a href="https://example.com/2">next</a> <a>bad</a></p>`,
+		},
+		{
+			name:     "void elements do not swallow what follows",
+			document: `<html><head><meta charset="utf-8"><link rel="stylesheet" href="/style.css"></head><body><div><input type="text"><wbr><p>` + paragraph + `</p></div></body></html>`,
+			want:     `<p>` + paragraph + `</p>`,
+		},
+		{
+			name:     "void elements are written without end tag",
+			document: `<article><p>` + paragraph + `<br><img src="/a.png" alt="A"></p><p>` + paragraph + `</p></article>`,
+			want:     `<p>` + paragraph + `<br><img src="https://example.com/a.png" alt="A"></p><p>` + paragraph + `</p>`,
+		},
+		{
+			name:     "escapes text",
+			document: `<article><p>` + paragraph + ` 1 &lt; 2 &amp; 3</p></article>`,
+			want:     `<p>` + paragraph + ` 1 &lt; 2 &amp; 3</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(strings.NewReader(tt.document), base, 10000)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractNoContent(t *testing.T) {
+	tests := []string{
+		``,
+		`<html><body><p>Too short.</p></body></html>`,
+		`<nav><p>` + paragraph + `</p></nav>`,
+	}
+	for _, document := range tests {
+		if got, err := Extract(strings.NewReader(document), nil, 10000); err == nil {
+			t.Errorf("Extract(%q) = %q, want an error", document, got)
+		}
+	}
+}
+
+func TestExtractMaxLength(t *testing.T) {
+	document := `<article><p>` + paragraph + `</p><p>` + paragraph + `</p></article>`
+	for _, maxLength := range []int{20, 90, 100, 200} {
+		got, err := Extract(strings.NewReader(document), nil, maxLength)
+		if err != nil {
+			continue
+		}
+		if len(got) > maxLength {
+			t.Errorf("Extract(maxLength=%d) returned %d bytes", maxLength, len(got))
+		}
+		if strings.Count(got, "<p>") != strings.Count(got, "</p>") {
+			t.Errorf("Extract(maxLength=%d) = %q, want balanced tags", maxLength, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package filters evaluates the filter rules of users against posts and
+// applies their actions.
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldContent     = "content"
+	FieldUrl         = "url"
+)
+
+const (
+	MatchContains = "contains"
+	MatchRegex    = "regex"
+)
+
+const (
+	ActionRead = "read"
+	ActionStar = "star"
+	ActionTag  = "tag"
+	ActionHide = "hide"
+)
+
+const existingPostsBatchSize = 500
+
+var Fields = []string{FieldTitle, FieldDescription, FieldContent, FieldUrl}
+
+var MatchTypes = []string{MatchContains, MatchRegex}
+
+var Actions = []string{ActionRead, ActionStar, ActionTag, ActionHide}
+
+// slashPattern matches the /pattern/flags notation of regular expressions.
+var slashPattern = regexp.MustCompile(`^/(.*)/([ims]*)$`)
+
+type Rule struct {
+	database.FilterRule
+	regex *regexp.Regexp
+}
+
+// Compile prepares a rule for evaluation.
+func Compile(rule database.FilterRule) (*Rule, error) {
+	compiled := &Rule{FilterRule: rule}
+	if rule.MatchType != MatchRegex {
+		return compiled, nil
+	}
+	regex, err := CompilePattern(rule.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiled.regex = regex
+	return compiled, nil
+}
+
+// CompilePattern compiles a regular expression written either in Go syntax or
+// as /pattern/flags, e.g. /sponsored/i.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if match := slashPattern.FindStringSubmatch(pattern); match != nil {
+		pattern = match[1]
+		if match[2] != "" {
+			pattern = "(?" + match[2] + ")" + pattern
+		}
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %v", err)
+	}
+	return regex, nil
+}
+
+// Matches reports whether the field of the post checked by the rule matches
+// its pattern. Contains matches are case-insensitive.
+func (r *Rule) Matches(post database.Post) bool {
+	if r.FeedID.Valid && r.FeedID.UUID != post.FeedID {
+		return false
+	}
+	var value string
+	switch r.Field {
+	case FieldTitle:
+		value = post.Title
+	case FieldDescription:
+		value = post.Description.String
+	case FieldContent:
+		value = post.Content.String
+	case FieldUrl:
+		value = post.Url
+	}
+	if value == "" {
+		return false
+	}
+	if r.regex != nil {
+		return r.regex.MatchString(value)
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(r.Pattern))
+}
+
+// Apply runs the actions of the rule on the post for the owner of the rule.
+func (r *Rule) Apply(ctx context.Context, db *database.Queries, post database.Post) error {
+	now := time.Now().UTC()
+	errs := []error{}
+	for _, action := range r.Actions {
+		var err error
+		switch action {
+		case ActionRead:
+			err = db.MarkPostAsRead(ctx, database.MarkPostAsReadParams{
+				UserID: r.UserID,
+				PostID: post.ID,
+				ReadAt: now,
+			})
+		case ActionStar:
+			err = db.StarPost(ctx, database.StarPostParams{
+				UserID:    r.UserID,
+				PostID:    post.ID,
+				StarredAt: now,
+			})
+		case ActionTag:
+			err = db.AddPostTag(ctx, database.AddPostTagParams{
+				UserID:    r.UserID,
+				PostID:    post.ID,
+				Tag:       r.Tag.String,
+				CreatedAt: now,
+			})
+		case ActionHide:
+			err = db.HidePost(ctx, database.HidePostParams{
+				UserID:   r.UserID,
+				PostID:   post.ID,
+				HiddenAt: now,
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("action %s: %v", action, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyToExistingPosts runs the rule on the posts already stored for the feeds
+// followed by its owner and returns the number of matching posts.
+func (r *Rule) ApplyToExistingPosts(ctx context.Context, db *database.Queries) (int64, error) {
+	matched := int64(0)
+	for offset := int32(0); ; offset += existingPostsBatchSize {
+		posts, err := db.GetPostsForFilterRule(ctx, database.GetPostsForFilterRuleParams{
+			UserID: r.UserID,
+			FeedID: r.FeedID,
+			Limit:  existingPostsBatchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return matched, err
+		}
+		for _, post := range posts {
+			if !r.Matches(post) {
+				continue
+			}
+			if err := r.Apply(ctx, db, post); err != nil {
+				return matched, err
+			}
+			matched++
+		}
+		if len(posts) < existingPostsBatchSize {
+			return matched, nil
+		}
+	}
+}
@@ -2,23 +2,37 @@ package infra
 
 import (
 	"log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Settings struct {
-	Port           string
-	DatabaseDriver string
-	DatabaseUrl    string
+	Port                        string
+	DatabaseDriver              string
+	DatabaseUrl                 string
+	BaseUrl                     string
+	ScraperMaxResponseSize      int
+	ScraperMaxItemsPerFetch     int
+	ScraperMaxTitleLength       int
+	ScraperMaxDescriptionLength int
 }
 
 func NewSettings() *Settings {
 	port := getEnvironmentVariable("PORT")
 	databaseDriver := getEnvironmentVariable("DATABASE_DRIVER")
 	databaseUrl := getEnvironmentVariable("DATABASE_URL")
+	baseUrl := getUrlEnvironmentVariable("BASE_URL")
 	return &Settings{
-		Port:           port,
-		DatabaseDriver: databaseDriver,
-		DatabaseUrl:    databaseUrl,
+		Port:                        port,
+		DatabaseDriver:              databaseDriver,
+		DatabaseUrl:                 databaseUrl,
+		BaseUrl:                     baseUrl,
+		ScraperMaxResponseSize:      getIntEnvironmentVariable("SCRAPER_MAX_RESPONSE_SIZE", 10<<20),
+		ScraperMaxItemsPerFetch:     getIntEnvironmentVariable("SCRAPER_MAX_ITEMS_PER_FETCH", 500),
+		ScraperMaxTitleLength:       getIntEnvironmentVariable("SCRAPER_MAX_TITLE_LENGTH", 500),
+		ScraperMaxDescriptionLength: getIntEnvironmentVariable("SCRAPER_MAX_DESCRIPTION_LENGTH", 20000),
 	}
 }
 
@@ -29,3 +43,28 @@ func getEnvironmentVariable(key string) string {
 	}
 	return env
 }
+
+// getUrlEnvironmentVariable reads a required absolute http or https URL,
+// without its trailing slash.
+func getUrlEnvironmentVariable(key string) string {
+	env := strings.TrimRight(getEnvironmentVariable(key), "/")
+	u, err := url.Parse(env)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		log.Fatalf("Environment variable %s must be an absolute http or https URL", key)
+	}
+	return env
+}
+
+// getIntEnvironmentVariable reads an optional positive integer, falling back
+// to defaultValue when the variable is not set.
+func getIntEnvironmentVariable(key string, defaultValue int) int {
+	env := os.Getenv(key)
+	if env == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(env)
+	if err != nil || value <= 0 {
+		log.Fatalf("Environment variable %s must be a positive integer", key)
+	}
+	return value
+}
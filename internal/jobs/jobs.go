@@ -0,0 +1,103 @@
+// Package jobs runs background jobs stored in a database table. The table is
+// the queue: workers claim the oldest pending job, so jobs outlive the
+// request that created them and the jobs left running by a stopped process
+// are queued again on startup.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = 30 * time.Second
+
+// Queue runs the jobs of one table with a fixed number of workers.
+type Queue[T any] struct {
+	Name    string
+	Workers int
+	// PollInterval is how often idle workers look for pending jobs, besides
+	// being woken by Notify.
+	PollInterval time.Duration
+	// Requeue moves the jobs left running by a previous process back to
+	// pending.
+	Requeue func(ctx context.Context) error
+	// Claim marks the oldest pending job as running and returns it, or
+	// returns sql.ErrNoRows when no job is pending.
+	Claim func(ctx context.Context) (T, error)
+	// Run processes a claimed job and records its result.
+	Run func(ctx context.Context, job T) error
+	// Fail records the error of a job whose Run failed or panicked.
+	Fail func(ctx context.Context, job T, err error)
+
+	wake     chan struct{}
+	wakeOnce sync.Once
+}
+
+func (q *Queue[T]) wakeChannel() chan struct{} {
+	q.wakeOnce.Do(func() {
+		q.wake = make(chan struct{}, 1)
+	})
+	return q.wake
+}
+
+// Start queues the interrupted jobs again and starts the workers.
+func (q *Queue[T]) Start() {
+	if err := q.Requeue(context.Background()); err != nil {
+		log.Printf("Error requeuing %v jobs: %v", q.Name, err)
+	}
+	for range max(q.Workers, 1) {
+		go q.work()
+	}
+}
+
+// Notify wakes an idle worker after a job was created.
+func (q *Queue[T]) Notify() {
+	select {
+	case q.wakeChannel() <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue[T]) work() {
+	pollInterval := q.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := q.Claim(context.Background())
+		if err == nil {
+			q.run(job)
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error claiming %v job: %v", q.Name, err)
+		}
+		select {
+		case <-q.wakeChannel():
+		case <-ticker.C:
+		}
+	}
+}
+
+// run processes a job, turning a panic into a failure of the job so that it
+// does not stay running forever nor stop the worker.
+func (q *Queue[T]) run(job T) {
+	ctx := context.Background()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Panic running %v job: %v", q.Name, recovered)
+			q.Fail(ctx, job, fmt.Errorf("internal error: %v", recovered))
+		}
+	}()
+	if err := q.Run(ctx, job); err != nil {
+		log.Printf("Error running %v job: %v", q.Name, err)
+		q.Fail(ctx, job, err)
+	}
+}
@@ -0,0 +1,119 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryQueue is a queue of named jobs held in memory, recording the outcome
+// of each job.
+type memoryQueue struct {
+	mu       sync.Mutex
+	pending  []string
+	requeued bool
+	outcomes map[string]string
+	done     chan struct{}
+}
+
+func newQueue(jobs []string, run func(job string) error) (*Queue[string], *memoryQueue) {
+	m := &memoryQueue{pending: jobs, outcomes: map[string]string{}, done: make(chan struct{}, len(jobs))}
+	record := func(job, outcome string) {
+		m.mu.Lock()
+		m.outcomes[job] = outcome
+		m.mu.Unlock()
+		m.done <- struct{}{}
+	}
+	q := &Queue[string]{
+		Name:         "test",
+		Workers:      2,
+		PollInterval: time.Hour,
+		Requeue: func(ctx context.Context) error {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.requeued = true
+			return nil
+		},
+		Claim: func(ctx context.Context) (string, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if len(m.pending) == 0 {
+				return "", sql.ErrNoRows
+			}
+			job := m.pending[0]
+			m.pending = m.pending[1:]
+			return job, nil
+		},
+		Run: func(ctx context.Context, job string) error {
+			if err := run(job); err != nil {
+				return err
+			}
+			record(job, "completed")
+			return nil
+		},
+		Fail: func(ctx context.Context, job string, err error) {
+			record(job, "failed: "+err.Error())
+		},
+	}
+	return q, m
+}
+
+func (m *memoryQueue) wait(t *testing.T, count int) {
+	t.Helper()
+	for range count {
+		select {
+		case <-m.done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the jobs, outcomes: %v", m.outcomes)
+		}
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q, m := newQueue([]string{"ok", "error", "panic"}, func(job string) error {
+		switch job {
+		case "error":
+			return errors.New("boom")
+		case "panic":
+			panic("crash")
+		}
+		return nil
+	})
+	q.Start()
+	m.wait(t, 3)
+	want := map[string]string{
+		"ok":    "completed",
+		"error": "failed: boom",
+		"panic": "failed: internal error: crash",
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.requeued {
+		t.Error("Start() did not requeue the interrupted jobs")
+	}
+	for job, outcome := range want {
+		if m.outcomes[job] != outcome {
+			t.Errorf("job %q outcome = %q, want %q", job, m.outcomes[job], outcome)
+		}
+	}
+}
+
+func TestQueueNotify(t *testing.T) {
+	q, m := newQueue(nil, func(job string) error { return nil })
+	q.Start()
+	// Let the workers find the queue empty and wait.
+	time.Sleep(10 * time.Millisecond)
+	m.mu.Lock()
+	m.pending = append(m.pending, "late")
+	m.mu.Unlock()
+	q.Notify()
+	m.wait(t, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.outcomes["late"] != "completed" {
+		t.Errorf("job %q outcome = %q, want completed", "late", m.outcomes["late"])
+	}
+}
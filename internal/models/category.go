@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+type CreateCategoryParams struct {
+	Name string `json:"name"`
+}
+
+func (b *CreateCategoryParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *CreateCategoryParams) Validate() error {
+	b.Name = strings.TrimSpace(b.Name)
+	if b.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type UpdateCategoryParams struct {
+	ID   uuid.UUID `json:"-"`
+	Name string    `json:"name"`
+}
+
+func (b *UpdateCategoryParams) Decode(categoryID string, r *http.Request) error {
+	id, err := parseUUIDParam("category id", categoryID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *UpdateCategoryParams) Validate() error {
+	b.Name = strings.TrimSpace(b.Name)
+	if b.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type DeleteCategoryParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *DeleteCategoryParams) Decode(categoryID string) error {
+	id, err := parseUUIDParam("category id", categoryID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type Category struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+}
+
+func NewCategoryFromDatabase(category database.Category) *Category {
+	return &Category{
+		ID:        category.ID,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+		UserID:    category.UserID,
+		Name:      category.Name,
+	}
+}
+
+func NewCategoriesFromDatabase(categories []database.Category) []*Category {
+	cs := make([]*Category, len(categories))
+	for i, category := range categories {
+		cs[i] = NewCategoryFromDatabase(category)
+	}
+	return cs
+}
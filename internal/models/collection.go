@@ -0,0 +1,177 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/pages"
+)
+
+const (
+	maxCollectionTitleLength       = 200
+	maxCollectionDescriptionLength = 2000
+)
+
+type CreateCollectionParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func (b *CreateCollectionParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *CreateCollectionParams) Validate() error {
+	b.Title = strings.TrimSpace(b.Title)
+	b.Description = strings.TrimSpace(b.Description)
+	if b.Title == "" {
+		return errors.New("title is required")
+	}
+	if utf8.RuneCountInString(b.Title) > maxCollectionTitleLength {
+		return fmt.Errorf("title must be at most %d characters long", maxCollectionTitleLength)
+	}
+	if utf8.RuneCountInString(b.Description) > maxCollectionDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters long", maxCollectionDescriptionLength)
+	}
+	return nil
+}
+
+func (b *CreateCollectionParams) NullDescription() sql.NullString {
+	return sql.NullString{String: b.Description, Valid: b.Description != ""}
+}
+
+type UpdateCollectionParams struct {
+	ID uuid.UUID `json:"-"`
+	CreateCollectionParams
+}
+
+func (b *UpdateCollectionParams) Decode(collectionID string, r *http.Request) error {
+	id, err := parseUUIDParam("collection id", collectionID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+type CollectionIDParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *CollectionIDParams) Decode(collectionID string) error {
+	id, err := parseUUIDParam("collection id", collectionID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type CollectionPostParams struct {
+	CollectionID uuid.UUID `json:"collection_id"`
+	PostID       uuid.UUID `json:"post_id"`
+}
+
+func (b *CollectionPostParams) Decode(collectionID string, postID string) error {
+	id, err := parseUUIDParam("collection id", collectionID)
+	if err != nil {
+		return err
+	}
+	b.CollectionID = id
+	id, err = parseUUIDParam("post id", postID)
+	if err != nil {
+		return err
+	}
+	b.PostID = id
+	return nil
+}
+
+type Collection struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UserID      uuid.UUID `json:"user_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
+
+func NewCollectionFromDatabase(collection database.Collection) *Collection {
+	return &Collection{
+		ID:          collection.ID,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+		UserID:      collection.UserID,
+		Title:       collection.Title,
+		Description: collection.Description.String,
+	}
+}
+
+func NewCollectionsFromDatabase(collections []database.Collection) []*Collection {
+	cs := make([]*Collection, len(collections))
+	for i, collection := range collections {
+		cs[i] = NewCollectionFromDatabase(collection)
+	}
+	return cs
+}
+
+const collectionSummaryLength = 300
+
+var htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+
+// plainText strips the tags and entities of an HTML fragment and collapses
+// its whitespace.
+func plainText(fragment string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(fragment, " "))), " ")
+}
+
+// summarize turns an HTML description into a plain text summary.
+func summarize(description string) string {
+	text := plainText(description)
+	if len(text) <= collectionSummaryLength {
+		return text
+	}
+	// Cut without splitting a rune.
+	length := collectionSummaryLength
+	for length > 0 && !utf8.RuneStart(text[length]) {
+		length--
+	}
+	return text[:length] + "…"
+}
+
+func NewCollectionPageFromDatabase(collection database.Collection, owner database.User, rssUrl string, posts []database.Post) pages.CollectionPage {
+	page := pages.CollectionPage{
+		Title:       collection.Title,
+		Description: plainText(collection.Description.String),
+		Author:      owner.Name,
+		RSSUrl:      rssUrl,
+		Posts:       make([]pages.CollectionPost, len(posts)),
+	}
+	for i, post := range posts {
+		page.Posts[i] = pages.CollectionPost{
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: summarize(post.Description.String),
+			Author:      post.Author.String,
+			PublishedAt: post.PublishedAt,
+		}
+	}
+	return page
+}
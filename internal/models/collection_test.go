@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+func TestNewCollectionPageFromDatabaseUsesPlainText(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"plain text", "Posts about Go", "Posts about Go"},
+		{"tags are stripped", "<p>Posts about <b>Go</b></p><script>x()</script>", "Posts about Go x()"},
+		{"entities are decoded", "Fish &amp; chips", "Fish & chips"},
+		{"whitespace is collapsed", "  a\n\n  b  ", "a b"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := database.Collection{
+				Title:       "Reading list",
+				Description: sql.NullString{String: tt.description, Valid: tt.description != ""},
+			}
+			post := database.Post{Title: "Post", Description: sql.NullString{String: tt.description, Valid: true}}
+			page := NewCollectionPageFromDatabase(collection, database.User{Name: "Ada"}, "/rss", []database.Post{post})
+			if page.Description != tt.want {
+				t.Errorf("page Description = %q, want %q", page.Description, tt.want)
+			}
+			if page.Posts[0].Description != tt.want {
+				t.Errorf("post Description = %q, want %q", page.Posts[0].Description, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeCutsLongDescriptions(t *testing.T) {
+	description := "<p>" + strings.Repeat("é", collectionSummaryLength) + "</p>"
+	got := summarize(description)
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("summarize() = %q, want an ellipsis", got)
+	}
+	if len(got) > collectionSummaryLength+len("…") {
+		t.Errorf("summarize() is %d bytes long, want at most %d", len(got), collectionSummaryLength+len("…"))
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("summarize() = %q split a rune", got)
+	}
+}
+
+func TestCollectionParamsLengths(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       string
+		description string
+		wantErr     bool
+	}{
+		{"ascii at the limit", strings.Repeat("a", maxCollectionTitleLength), strings.Repeat("a", maxCollectionDescriptionLength), false},
+		{"accents count as one character", strings.Repeat("é", maxCollectionTitleLength), strings.Repeat("é", maxCollectionDescriptionLength), false},
+		{"title too long", strings.Repeat("é", maxCollectionTitleLength+1), "", true},
+		{"description too long", "Reading list", strings.Repeat("é", maxCollectionDescriptionLength+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := UpdateCollectionParams{CreateCollectionParams: CreateCollectionParams{Title: tt.title, Description: tt.description}}
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
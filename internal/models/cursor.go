@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CursorNext = "next"
+	CursorPrev = "prev"
+)
+
+// PostCursor is a position in a sorted list of posts, made of the sort value
+// and id of a post. A next cursor points to the posts after it in the sort
+// order, a prev cursor to the posts before it.
+type PostCursor struct {
+	Time      time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+	Direction string    `json:"d"`
+	Sort      string    `json:"s"`
+}
+
+// Encode returns the cursor as an opaque string clients send back as is.
+func (c *PostCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePostCursor(cursor string) (*PostCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	c := &PostCursor{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if c.ID == uuid.Nil || c.Time.IsZero() || (c.Direction != CursorNext && c.Direction != CursorPrev) {
+		return nil, errors.New("invalid cursor")
+	}
+	return c, nil
+}
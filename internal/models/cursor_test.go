@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPostCursorRoundTrip(t *testing.T) {
+	id := uuid.MustParse("7f1f8e2c-52a4-4f5a-9a44-6a3b0d9c1e10")
+	published := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	tests := []struct {
+		name   string
+		cursor PostCursor
+	}{
+		{"next by published_at", PostCursor{Time: published, ID: id, Direction: CursorNext, Sort: "published_at"}},
+		{"prev by created_at", PostCursor{Time: published, ID: id, Direction: CursorPrev, Sort: "created_at"}},
+		{"time zone offset", PostCursor{Time: published.In(time.FixedZone("UTC-3", -3*3600)), ID: id, Direction: CursorNext}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePostCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodePostCursor() error = %v", err)
+			}
+			if !got.Time.Equal(tt.cursor.Time) || got.ID != tt.cursor.ID || got.Direction != tt.cursor.Direction || got.Sort != tt.cursor.Sort {
+				t.Errorf("DecodePostCursor() = %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodePostCursorErrors(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"t":"2024-05-01T12:30:00Z"}`))},
+		{"not json", encode("cursor")},
+		{"missing id", encode(`{"t":"2024-05-01T12:30:00Z","d":"next"}`)},
+		{"missing time", encode(`{"i":"7f1f8e2c-52a4-4f5a-9a44-6a3b0d9c1e10","d":"next"}`)},
+		{"unknown direction", encode(`{"t":"2024-05-01T12:30:00Z","i":"7f1f8e2c-52a4-4f5a-9a44-6a3b0d9c1e10","d":"up"}`)},
+		{"invalid id", encode(`{"t":"2024-05-01T12:30:00Z","i":"x","d":"next"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := DecodePostCursor(tt.cursor); err == nil {
+				t.Errorf("DecodePostCursor(%q) = %+v, want an error", tt.cursor, got)
+			}
+		})
+	}
+}
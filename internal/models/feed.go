@@ -1,20 +1,25 @@
 package models
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/urls"
 )
 
 type CreateFeedParams struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
+	Name             string `json:"name"`
+	Url              string `json:"url"`
+	FetchFullContent bool   `json:"fetch_full_content"`
+	CanonicalUrl     string `json:"-"`
 }
 
 func (b *CreateFeedParams) Decode(r *http.Request) error {
@@ -30,44 +35,269 @@ func (b *CreateFeedParams) Validate() error {
 	if b.Name == "" {
 		return errors.New("name is required")
 	}
-	if b.Url == "" {
-		return errors.New("url is required")
+	canonicalUrl, _, err := validateFeedUrl(b.Url)
+	if err != nil {
+		return err
+	}
+	b.CanonicalUrl = canonicalUrl
+	return nil
+}
+
+// validateFeedUrl checks that the URL serves an RSS feed and returns its
+// canonical form with the feed.
+func validateFeedUrl(feedUrl string) (string, RSSFeed, error) {
+	canonicalUrl, err := canonicalizeFeedUrl(feedUrl)
+	if err != nil {
+		return "", RSSFeed{}, err
 	}
-	isValidUrl := strings.HasPrefix(b.Url, "https://") || strings.HasPrefix(b.Url, "http://")
+	rssFeed, err := GetRSSFeedFromURL(feedUrl)
+	if err != nil {
+		return "", RSSFeed{}, fmt.Errorf("invalid RSS URL: %v", err)
+	}
+	return canonicalUrl, rssFeed, nil
+}
+
+func canonicalizeFeedUrl(feedUrl string) (string, error) {
+	if feedUrl == "" {
+		return "", errors.New("url is required")
+	}
+	isValidUrl := strings.HasPrefix(feedUrl, "https://") || strings.HasPrefix(feedUrl, "http://")
 	if !isValidUrl {
-		return errors.New("url must start with https:// or http://")
+		return "", errors.New("url must start with https:// or http://")
+	}
+	canonicalUrl, err := urls.Canonicalize(feedUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %v", err)
+	}
+	return canonicalUrl, nil
+}
+
+type GetFeedParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *GetFeedParams) Decode(feedID string) error {
+	id, err := parseUUIDParam("feed id", feedID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+// UpdateFeedParams holds the fields of a feed to change, the others being nil.
+type UpdateFeedParams struct {
+	ID               uuid.UUID `json:"-"`
+	Name             *string   `json:"name"`
+	Url              *string   `json:"url"`
+	FetchFullContent *bool     `json:"fetch_full_content"`
+	CanonicalUrl     string    `json:"-"`
+}
+
+func (b *UpdateFeedParams) Decode(feedID string, r *http.Request) error {
+	id, err := parseUUIDParam("feed id", feedID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks the given fields, re-running the RSS validation when the
+// URL changes.
+func (b *UpdateFeedParams) Validate() error {
+	if b.Name == nil && b.Url == nil && b.FetchFullContent == nil {
+		return errors.New("at least one of name, url or fetch_full_content is required")
+	}
+	if b.Name != nil {
+		name := strings.TrimSpace(*b.Name)
+		if name == "" {
+			return errors.New("name must not be empty")
+		}
+		b.Name = &name
+	}
+	if b.Url != nil {
+		canonicalUrl, _, err := validateFeedUrl(*b.Url)
+		if err != nil {
+			return err
+		}
+		b.CanonicalUrl = canonicalUrl
 	}
-	_, err := GetRSSFeedFromURL(b.Url)
+	return nil
+}
+
+// ApplyTo returns the update of the feed, keeping the fields that are not given.
+func (b *UpdateFeedParams) ApplyTo(feed database.Feed) database.UpdateFeedParams {
+	update := database.UpdateFeedParams{
+		ID:               feed.ID,
+		Name:             feed.Name,
+		Url:              feed.Url,
+		CanonicalUrl:     feed.CanonicalUrl,
+		FetchFullContent: feed.FetchFullContent,
+	}
+	if b.Name != nil {
+		update.Name = *b.Name
+	}
+	if b.Url != nil {
+		update.Url = *b.Url
+		update.CanonicalUrl = b.CanonicalUrl
+	}
+	if b.FetchFullContent != nil {
+		update.FetchFullContent = *b.FetchFullContent
+	}
+	return update
+}
+
+type DeleteFeedParams struct {
+	ID    uuid.UUID `json:"id"`
+	Force bool      `json:"force"`
+}
+
+func (b *DeleteFeedParams) Decode(feedID string, r *http.Request) error {
+	id, err := parseUUIDParam("feed id", feedID)
 	if err != nil {
-		return fmt.Errorf("invalid RSS URL: %v", err)
+		return err
+	}
+	b.ID = id
+	if value := r.URL.Query().Get("force"); value != "" {
+		force, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid force: %v", err)
+		}
+		b.Force = force
+	}
+	return nil
+}
+
+const (
+	FeedSortRecent    = "recent"
+	FeedSortFollowers = "followers"
+	FeedSortActive    = "active"
+)
+
+// likeEscaper escapes the LIKE wildcards so a search matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type GetFeedsParams struct {
+	Query    sql.NullString `json:"q"`
+	Sort     string         `json:"sort"`
+	Language sql.NullString `json:"language"`
+}
+
+func (p *GetFeedsParams) Decode(r *http.Request) error {
+	if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+		p.Query = sql.NullString{String: likeEscaper.Replace(query), Valid: true}
+	}
+	p.Sort = r.URL.Query().Get("sort")
+	if p.Sort == "" {
+		p.Sort = FeedSortRecent
+	}
+	if language := strings.TrimSpace(r.URL.Query().Get("language")); language != "" {
+		p.Language = sql.NullString{String: likeEscaper.Replace(language), Valid: true}
+	}
+	return nil
+}
+
+func (p *GetFeedsParams) Validate() error {
+	if p.Sort != FeedSortRecent && p.Sort != FeedSortFollowers && p.Sort != FeedSortActive {
+		return errors.New("sort must be one of recent, followers or active")
 	}
 	return nil
 }
 
 type Feed struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Name      string    `json:"name"`
-	Url       string    `json:"url"`
-	UserID    uuid.UUID `json:"user_id"`
+	ID               uuid.UUID `json:"id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Name             string    `json:"name"`
+	Url              string    `json:"url"`
+	UserID           uuid.UUID `json:"user_id"`
+	FetchFullContent bool      `json:"fetch_full_content"`
+	CanonicalUrl     string    `json:"canonical_url"`
+	LastWarning      string    `json:"last_warning"`
+	Description      string    `json:"description"`
+	Language         string    `json:"language"`
+	FollowerCount    int64     `json:"follower_count"`
 }
 
 func NewFeedFromDatabase(feed database.Feed) *Feed {
 	return &Feed{
-		ID:        feed.ID,
-		CreatedAt: feed.CreatedAt,
-		UpdatedAt: feed.UpdatedAt,
-		Name:      feed.Name,
-		Url:       feed.Url,
-		UserID:    feed.UserID,
+		ID:               feed.ID,
+		CreatedAt:        feed.CreatedAt,
+		UpdatedAt:        feed.UpdatedAt,
+		Name:             feed.Name,
+		Url:              feed.Url,
+		UserID:           feed.UserID,
+		FetchFullContent: feed.FetchFullContent,
+		CanonicalUrl:     feed.CanonicalUrl,
+		LastWarning:      feed.LastWarning.String,
+		Description:      feed.Description.String,
+		Language:         feed.Language.String,
 	}
 }
 
-func NewFeedsFromDatabase(feeds []database.Feed) []*Feed {
+func NewFeedsFromDatabase(feeds []database.GetAllFeedsRow) []*Feed {
 	fs := make([]*Feed, len(feeds))
 	for i, feed := range feeds {
-		fs[i] = NewFeedFromDatabase(feed)
+		fs[i] = NewFeedFromDatabase(database.Feed{
+			ID:               feed.ID,
+			CreatedAt:        feed.CreatedAt,
+			UpdatedAt:        feed.UpdatedAt,
+			Name:             feed.Name,
+			Url:              feed.Url,
+			UserID:           feed.UserID,
+			LastFetchedAt:    feed.LastFetchedAt,
+			FetchFullContent: feed.FetchFullContent,
+			CanonicalUrl:     feed.CanonicalUrl,
+			LastWarning:      feed.LastWarning,
+			Description:      feed.Description,
+			Language:         feed.Language,
+			LastError:        feed.LastError,
+		})
+		fs[i].FollowerCount = feed.FollowerCount
 	}
 	return fs
 }
+
+// statsWeeks is the number of weeks over which posts_per_week is averaged.
+const statsWeeks = 4
+
+// FeedStatsSince returns the start of the window of the recent posts counted
+// by posts_per_week.
+func FeedStatsSince(now time.Time) time.Time {
+	return now.AddDate(0, 0, -7*statsWeeks)
+}
+
+type FeedStats struct {
+	FollowerCount int64      `json:"follower_count"`
+	PostCount     int64      `json:"post_count"`
+	PostsPerWeek  float64    `json:"posts_per_week"`
+	LastPostAt    *time.Time `json:"last_post_at"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	LastError     string     `json:"last_error"`
+}
+
+type FeedWithStats struct {
+	*Feed
+	Stats FeedStats `json:"stats"`
+}
+
+func NewFeedWithStatsFromDatabase(feed database.Feed, stats database.GetFeedStatsRow) *FeedWithStats {
+	f := NewFeedFromDatabase(feed)
+	f.FollowerCount = stats.FollowerCount
+	return &FeedWithStats{
+		Feed: f,
+		Stats: FeedStats{
+			FollowerCount: stats.FollowerCount,
+			PostCount:     stats.PostCount,
+			PostsPerWeek:  float64(stats.RecentPostCount) / statsWeeks,
+			LastPostAt:    nullTimeToPointer(stats.LastPostAt),
+			LastFetchedAt: nullTimeToPointer(feed.LastFetchedAt),
+			LastError:     feed.LastError.String,
+		},
+	}
+}
@@ -1,10 +1,14 @@
 package models
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
@@ -22,6 +26,112 @@ func (b *CreateFeedFollowParams) Decode(r *http.Request) error {
 	return nil
 }
 
+type GetFeedFollowsParams struct {
+	CategoryID uuid.NullUUID `json:"category_id"`
+}
+
+func (p *GetFeedFollowsParams) Decode(r *http.Request) error {
+	categoryID, err := parseOptionalUUIDQuery(r, "category_id")
+	if err != nil {
+		return err
+	}
+	p.CategoryID = categoryID
+	return nil
+}
+
+type SetFeedFollowCategoryParams struct {
+	ID         uuid.UUID  `json:"-"`
+	CategoryID *uuid.UUID `json:"category_id"`
+}
+
+func (b *SetFeedFollowCategoryParams) Decode(feedFollowID string, r *http.Request) error {
+	id, err := parseUUIDParam("feed follow id", feedFollowID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+const (
+	maxCustomTitleLength = 200
+	maxNotesLength       = 2000
+	maxPriority          = 100
+)
+
+// UpdateFeedFollowParams holds the overrides of a followed feed to change,
+// the others being nil. An empty title or notes clears it.
+type UpdateFeedFollowParams struct {
+	ID               uuid.UUID `json:"-"`
+	CustomTitle      *string   `json:"custom_title"`
+	Notes            *string   `json:"notes"`
+	Priority         *int32    `json:"priority"`
+	HideFromTimeline *bool     `json:"hide_from_timeline"`
+}
+
+func (b *UpdateFeedFollowParams) Decode(feedFollowID string, r *http.Request) error {
+	id, err := parseUUIDParam("feed follow id", feedFollowID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *UpdateFeedFollowParams) Validate() error {
+	if b.CustomTitle == nil && b.Notes == nil && b.Priority == nil && b.HideFromTimeline == nil {
+		return errors.New("at least one of custom_title, notes, priority or hide_from_timeline is required")
+	}
+	if b.CustomTitle != nil {
+		customTitle := strings.TrimSpace(*b.CustomTitle)
+		if utf8.RuneCountInString(customTitle) > maxCustomTitleLength {
+			return fmt.Errorf("custom_title must be at most %d characters long", maxCustomTitleLength)
+		}
+		b.CustomTitle = &customTitle
+	}
+	if b.Notes != nil && utf8.RuneCountInString(*b.Notes) > maxNotesLength {
+		return fmt.Errorf("notes must be at most %d characters long", maxNotesLength)
+	}
+	if b.Priority != nil && (*b.Priority < -maxPriority || *b.Priority > maxPriority) {
+		return fmt.Errorf("priority must be between %d and %d", -maxPriority, maxPriority)
+	}
+	return nil
+}
+
+// ApplyTo returns the update of the feed follow, keeping the fields that are
+// not given.
+func (b *UpdateFeedFollowParams) ApplyTo(feedFollow database.FeedFollow) database.UpdateFeedFollowParams {
+	update := database.UpdateFeedFollowParams{
+		ID:               feedFollow.ID,
+		UserID:           feedFollow.UserID,
+		CustomTitle:      feedFollow.CustomTitle,
+		Notes:            feedFollow.Notes,
+		Priority:         feedFollow.Priority,
+		HideFromTimeline: feedFollow.HideFromTimeline,
+	}
+	if b.CustomTitle != nil {
+		update.CustomTitle = sql.NullString{String: *b.CustomTitle, Valid: *b.CustomTitle != ""}
+	}
+	if b.Notes != nil {
+		update.Notes = sql.NullString{String: *b.Notes, Valid: *b.Notes != ""}
+	}
+	if b.Priority != nil {
+		update.Priority = *b.Priority
+	}
+	if b.HideFromTimeline != nil {
+		update.HideFromTimeline = *b.HideFromTimeline
+	}
+	return update
+}
+
 type DeleteFeedFollowParams struct {
 	ID uuid.UUID `json:"id"`
 }
@@ -39,20 +149,30 @@ func (b *DeleteFeedFollowParams) Decode(feedFollowID string) error {
 }
 
 type FeedFollow struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	FeedID    uuid.UUID `json:"feed_id"`
+	ID               uuid.UUID  `json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	UserID           uuid.UUID  `json:"user_id"`
+	FeedID           uuid.UUID  `json:"feed_id"`
+	CategoryID       *uuid.UUID `json:"category_id"`
+	CustomTitle      string     `json:"custom_title"`
+	Notes            string     `json:"notes"`
+	Priority         int32      `json:"priority"`
+	HideFromTimeline bool       `json:"hide_from_timeline"`
 }
 
 func NewFeedFollowFromDatabase(feedFollow database.FeedFollow) *FeedFollow {
 	return &FeedFollow{
-		ID:        feedFollow.ID,
-		CreatedAt: feedFollow.CreatedAt,
-		UpdatedAt: feedFollow.UpdatedAt,
-		UserID:    feedFollow.UserID,
-		FeedID:    feedFollow.FeedID,
+		ID:               feedFollow.ID,
+		CreatedAt:        feedFollow.CreatedAt,
+		UpdatedAt:        feedFollow.UpdatedAt,
+		UserID:           feedFollow.UserID,
+		FeedID:           feedFollow.FeedID,
+		CategoryID:       nullUUIDToPointer(feedFollow.CategoryID),
+		CustomTitle:      feedFollow.CustomTitle.String,
+		Notes:            feedFollow.Notes.String,
+		Priority:         feedFollow.Priority,
+		HideFromTimeline: feedFollow.HideFromTimeline,
 	}
 }
 
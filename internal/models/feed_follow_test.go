@@ -0,0 +1,28 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateFeedFollowParamsLengths(t *testing.T) {
+	tests := []struct {
+		name        string
+		customTitle string
+		notes       string
+		wantErr     bool
+	}{
+		{"ascii at the limit", strings.Repeat("a", maxCustomTitleLength), strings.Repeat("a", maxNotesLength), false},
+		{"accents count as one character", strings.Repeat("é", maxCustomTitleLength), strings.Repeat("é", maxNotesLength), false},
+		{"custom title too long", strings.Repeat("é", maxCustomTitleLength+1), "", true},
+		{"notes too long", "", strings.Repeat("é", maxNotesLength+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := UpdateFeedFollowParams{CustomTitle: &tt.customTitle, Notes: &tt.notes}
+			if err := params.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
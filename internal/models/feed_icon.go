@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type GetFeedIconParams struct {
+	FeedID uuid.UUID `json:"feed_id"`
+}
+
+func (b *GetFeedIconParams) Decode(feedID string) error {
+	id, err := parseUUIDParam("feed id", feedID)
+	if err != nil {
+		return err
+	}
+	b.FeedID = id
+	return nil
+}
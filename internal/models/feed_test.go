@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+func TestFeedStatsWindow(t *testing.T) {
+	now := time.Date(2024, 5, 29, 12, 0, 0, 0, time.UTC)
+	since := FeedStatsSince(now)
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC); !since.Equal(want) {
+		t.Errorf("FeedStatsSince() = %v, want %v", since, want)
+	}
+	// The posts counted since then are averaged over as many weeks.
+	stats := NewFeedWithStatsFromDatabase(database.Feed{}, database.GetFeedStatsRow{RecentPostCount: 10})
+	weeks := now.Sub(since).Hours() / (24 * 7)
+	if want := 10 / weeks; stats.Stats.PostsPerWeek != want {
+		t.Errorf("PostsPerWeek = %v, want %v", stats.Stats.PostsPerWeek, want)
+	}
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/syndication"
+)
+
+// Kinds of output feeds, each listing different posts of a user.
+const (
+	OutputFeedTimeline = "timeline"
+	OutputFeedCategory = "category"
+	OutputFeedTag      = "tag"
+	OutputFeedStarred  = "starred"
+)
+
+var OutputFeedKinds = []string{OutputFeedTimeline, OutputFeedCategory, OutputFeedTag, OutputFeedStarred}
+
+type CreateFeedTokenParams struct {
+	Kind       string     `json:"kind"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	Tag        string     `json:"tag"`
+}
+
+func (b *CreateFeedTokenParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks that the category or the tag is given for the kinds that
+// need it, and only for them.
+func (b *CreateFeedTokenParams) Validate() error {
+	if !slices.Contains(OutputFeedKinds, b.Kind) {
+		return fmt.Errorf("kind must be one of %s", strings.Join(OutputFeedKinds, ", "))
+	}
+	b.Tag = NormalizeTag(b.Tag)
+	if b.Kind == OutputFeedCategory {
+		if b.CategoryID == nil {
+			return errors.New("category_id is required by the category kind")
+		}
+	} else {
+		b.CategoryID = nil
+	}
+	if b.Kind == OutputFeedTag {
+		if b.Tag == "" {
+			return errors.New("tag is required by the tag kind")
+		}
+		if utf8.RuneCountInString(b.Tag) > maxTagLength {
+			return fmt.Errorf("tag must be at most %d characters long", maxTagLength)
+		}
+	} else {
+		b.Tag = ""
+	}
+	return nil
+}
+
+func (b *CreateFeedTokenParams) NullCategoryID() uuid.NullUUID {
+	if b.CategoryID == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *b.CategoryID, Valid: true}
+}
+
+func (b *CreateFeedTokenParams) NullTag() sql.NullString {
+	return sql.NullString{String: b.Tag, Valid: b.Tag != ""}
+}
+
+type FeedTokenIDParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *FeedTokenIDParams) Decode(feedTokenID string) error {
+	id, err := parseUUIDParam("feed token id", feedTokenID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+// GetOutputFeedParams reads the output feed requested as
+// /users/{userID}/feeds/{kind}.{format}?token=...
+type GetOutputFeedParams struct {
+	UserID uuid.UUID
+	Kind   string
+	Format string
+	Token  string
+}
+
+func (p *GetOutputFeedParams) Decode(userID string, file string, r *http.Request) error {
+	id, err := parseUUIDParam("user id", userID)
+	if err != nil {
+		return err
+	}
+	p.UserID = id
+	dot := strings.LastIndex(file, ".")
+	if dot < 0 {
+		return errors.New("feed must end with .xml, .atom or .json")
+	}
+	p.Kind = file[:dot]
+	p.Format = file[dot+1:]
+	p.Token = r.URL.Query().Get("token")
+	return nil
+}
+
+func (p *GetOutputFeedParams) Validate() error {
+	if !slices.Contains(OutputFeedKinds, p.Kind) {
+		return fmt.Errorf("feed must be one of %s", strings.Join(OutputFeedKinds, ", "))
+	}
+	if !slices.Contains(syndication.Formats, p.Format) {
+		return errors.New("feed must end with .xml, .atom or .json")
+	}
+	if p.Token == "" {
+		return errors.New("token is required")
+	}
+	return nil
+}
+
+// Matches reports whether the token grants access to the requested feed.
+func (p *GetOutputFeedParams) Matches(feedToken database.FeedToken) bool {
+	return feedToken.UserID == p.UserID && feedToken.Kind == p.Kind
+}
+
+type FeedTokenUrls struct {
+	RSS  string `json:"rss"`
+	Atom string `json:"atom"`
+	JSON string `json:"json"`
+}
+
+type FeedToken struct {
+	ID         uuid.UUID     `json:"id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Kind       string        `json:"kind"`
+	CategoryID *uuid.UUID    `json:"category_id"`
+	Tag        string        `json:"tag"`
+	Token      string        `json:"token"`
+	Urls       FeedTokenUrls `json:"urls"`
+}
+
+// OutputFeedPath returns the path of an output feed in the given format.
+func OutputFeedPath(feedToken database.FeedToken, format string) string {
+	return fmt.Sprintf("/v1/users/%s/feeds/%s.%s?token=%s", feedToken.UserID, feedToken.Kind, format, feedToken.Token)
+}
+
+func NewFeedTokenFromDatabase(feedToken database.FeedToken) *FeedToken {
+	return &FeedToken{
+		ID:         feedToken.ID,
+		CreatedAt:  feedToken.CreatedAt,
+		UserID:     feedToken.UserID,
+		Kind:       feedToken.Kind,
+		CategoryID: nullUUIDToPointer(feedToken.CategoryID),
+		Tag:        feedToken.Tag.String,
+		Token:      feedToken.Token,
+		Urls: FeedTokenUrls{
+			RSS:  OutputFeedPath(feedToken, syndication.FormatRSS),
+			Atom: OutputFeedPath(feedToken, syndication.FormatAtom),
+			JSON: OutputFeedPath(feedToken, syndication.FormatJSON),
+		},
+	}
+}
+
+func NewFeedTokensFromDatabase(feedTokens []database.FeedToken) []*FeedToken {
+	ts := make([]*FeedToken, len(feedTokens))
+	for i, feedToken := range feedTokens {
+		ts[i] = NewFeedTokenFromDatabase(feedToken)
+	}
+	return ts
+}
+
+// NewOutputFeedFromDatabase builds the output feed of posts, identified by the
+// id of the record it is made of, served at selfUrl about the page at siteUrl
+// and updated at the date of its latest post.
+func NewOutputFeedFromDatabase(id uuid.UUID, title string, author string, selfUrl string, siteUrl string, posts []database.Post) syndication.Feed {
+	feed := syndication.Feed{
+		ID:          "urn:uuid:" + id.String(),
+		Title:       title,
+		Description: title,
+		SelfUrl:     selfUrl,
+		SiteUrl:     siteUrl,
+		Author:      author,
+		Items:       make([]syndication.Item, len(posts)),
+	}
+	for i, post := range posts {
+		feed.Items[i] = syndication.Item{
+			ID:            post.ID.String(),
+			Title:         post.Title,
+			Url:           post.Url,
+			Summary:       post.Description.String,
+			Content:       post.Content.String,
+			Author:        post.Author.String,
+			PublishedAt:   post.PublishedAt,
+			EnclosureUrl:  post.EnclosureUrl.String,
+			EnclosureType: post.EnclosureType.String,
+		}
+		if post.PublishedAt.After(feed.Updated) {
+			feed.Updated = post.PublishedAt
+		}
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+	return feed
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/filters"
+)
+
+const maxPatternLength = 500
+
+type CreateFilterRuleParams struct {
+	FeedID    *uuid.UUID `json:"feed_id"`
+	Field     string     `json:"field"`
+	MatchType string     `json:"match_type"`
+	Pattern   string     `json:"pattern"`
+	Actions   []string   `json:"actions"`
+	Tag       string     `json:"tag"`
+}
+
+func (b *CreateFilterRuleParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks the rule and normalizes its actions and tag.
+func (b *CreateFilterRuleParams) Validate() error {
+	if !slices.Contains(filters.Fields, b.Field) {
+		return fmt.Errorf("field must be one of %s", strings.Join(filters.Fields, ", "))
+	}
+	if !slices.Contains(filters.MatchTypes, b.MatchType) {
+		return fmt.Errorf("match_type must be one of %s", strings.Join(filters.MatchTypes, ", "))
+	}
+	if b.Pattern == "" {
+		return errors.New("pattern is required")
+	}
+	if len(b.Pattern) > maxPatternLength {
+		return fmt.Errorf("pattern must be at most %d characters long", maxPatternLength)
+	}
+	if b.MatchType == filters.MatchRegex {
+		if _, err := filters.CompilePattern(b.Pattern); err != nil {
+			return err
+		}
+	}
+	if len(b.Actions) == 0 {
+		return errors.New("actions is required")
+	}
+	actions := []string{}
+	for _, action := range b.Actions {
+		if !slices.Contains(filters.Actions, action) {
+			return fmt.Errorf("actions must be among %s", strings.Join(filters.Actions, ", "))
+		}
+		if !slices.Contains(actions, action) {
+			actions = append(actions, action)
+		}
+	}
+	b.Actions = actions
+	b.Tag = NormalizeTag(b.Tag)
+	if slices.Contains(b.Actions, filters.ActionTag) {
+		if b.Tag == "" {
+			return errors.New("tag is required by the tag action")
+		}
+		if utf8.RuneCountInString(b.Tag) > maxTagLength {
+			return fmt.Errorf("tag must be at most %d characters long", maxTagLength)
+		}
+	} else {
+		b.Tag = ""
+	}
+	return nil
+}
+
+func (b *CreateFilterRuleParams) NullFeedID() uuid.NullUUID {
+	if b.FeedID == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *b.FeedID, Valid: true}
+}
+
+func (b *CreateFilterRuleParams) NullTag() sql.NullString {
+	return sql.NullString{String: b.Tag, Valid: b.Tag != ""}
+}
+
+type UpdateFilterRuleParams struct {
+	ID uuid.UUID `json:"-"`
+	CreateFilterRuleParams
+}
+
+func (b *UpdateFilterRuleParams) Decode(filterRuleID string, r *http.Request) error {
+	id, err := parseUUIDParam("filter rule id", filterRuleID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+type FilterRuleIDParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *FilterRuleIDParams) Decode(filterRuleID string) error {
+	id, err := parseUUIDParam("filter rule id", filterRuleID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type FilterRule struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id"`
+	FeedID    *uuid.UUID `json:"feed_id"`
+	Field     string     `json:"field"`
+	MatchType string     `json:"match_type"`
+	Pattern   string     `json:"pattern"`
+	Actions   []string   `json:"actions"`
+	Tag       string     `json:"tag"`
+}
+
+func NewFilterRuleFromDatabase(rule database.FilterRule) *FilterRule {
+	return &FilterRule{
+		ID:        rule.ID,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+		UserID:    rule.UserID,
+		FeedID:    nullUUIDToPointer(rule.FeedID),
+		Field:     rule.Field,
+		MatchType: rule.MatchType,
+		Pattern:   rule.Pattern,
+		Actions:   rule.Actions,
+		Tag:       rule.Tag.String,
+	}
+}
+
+func NewFilterRulesFromDatabase(rules []database.FilterRule) []*FilterRule {
+	rs := make([]*FilterRule, len(rules))
+	for i, rule := range rules {
+		rs[i] = NewFilterRuleFromDatabase(rule)
+	}
+	return rs
+}
+
+// Statuses of a run of a rule on the posts already stored.
+const (
+	FilterRuleRunPending   = "pending"
+	FilterRuleRunRunning   = "running"
+	FilterRuleRunCompleted = "completed"
+	FilterRuleRunFailed    = "failed"
+)
+
+type FilterRuleRunIDParams struct {
+	FilterRuleID uuid.UUID `json:"filter_rule_id"`
+	ID           uuid.UUID `json:"id"`
+}
+
+func (b *FilterRuleRunIDParams) Decode(filterRuleID string, runID string) error {
+	id, err := parseUUIDParam("filter rule id", filterRuleID)
+	if err != nil {
+		return err
+	}
+	b.FilterRuleID = id
+	id, err = parseUUIDParam("run id", runID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type FilterRuleRun struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	FilterRuleID uuid.UUID `json:"filter_rule_id"`
+	Status       string    `json:"status"`
+	Matched      int64     `json:"matched"`
+	Error        string    `json:"error"`
+}
+
+func NewFilterRuleRunFromDatabase(run database.FilterRuleRun) *FilterRuleRun {
+	return &FilterRuleRun{
+		ID:           run.ID,
+		CreatedAt:    run.CreatedAt,
+		UpdatedAt:    run.UpdatedAt,
+		FilterRuleID: run.FilterRuleID,
+		Status:       run.Status,
+		Matched:      run.Matched,
+		Error:        run.Error.String,
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+const (
+	MuteKindWord   = "word"
+	MuteKindDomain = "domain"
+	MuteKindAuthor = "author"
+)
+
+const maxMuteValueLength = 255
+
+type CreateMuteParams struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+func (b *CreateMuteParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate lowercases the value, which is matched case-insensitively, and
+// reduces a muted domain given as a URL to its host.
+func (b *CreateMuteParams) Validate() error {
+	if b.Kind != MuteKindWord && b.Kind != MuteKindDomain && b.Kind != MuteKindAuthor {
+		return errors.New("kind must be one of word, domain or author")
+	}
+	b.Value = strings.ToLower(strings.TrimSpace(b.Value))
+	if b.Kind == MuteKindDomain {
+		if strings.Contains(b.Value, "://") {
+			u, err := url.Parse(b.Value)
+			if err != nil {
+				return fmt.Errorf("invalid domain: %v", err)
+			}
+			b.Value = u.Hostname()
+		}
+		b.Value = strings.Trim(b.Value, ".")
+		if strings.ContainsAny(b.Value, "/:?# ") {
+			return errors.New("value must be a domain such as example.com")
+		}
+	}
+	if b.Value == "" {
+		return errors.New("value is required")
+	}
+	if len(b.Value) > maxMuteValueLength {
+		return fmt.Errorf("value must be at most %d characters long", maxMuteValueLength)
+	}
+	return nil
+}
+
+type DeleteMuteParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *DeleteMuteParams) Decode(muteID string) error {
+	id, err := parseUUIDParam("mute id", muteID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type Mute struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+}
+
+func NewMuteFromDatabase(mute database.Mute) *Mute {
+	return &Mute{
+		ID:        mute.ID,
+		CreatedAt: mute.CreatedAt,
+		UserID:    mute.UserID,
+		Kind:      mute.Kind,
+		Value:     mute.Value,
+	}
+}
+
+func NewMutesFromDatabase(mutes []database.Mute) []*Mute {
+	ms := make([]*Mute, len(mutes))
+	for i, mute := range mutes {
+		ms[i] = NewMuteFromDatabase(mute)
+	}
+	return ms
+}
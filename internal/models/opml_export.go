@@ -0,0 +1,27 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/opml"
+)
+
+// NewOpmlFromDatabase builds the OPML document of the feeds followed by a
+// user, titled with their custom title when they set one.
+func NewOpmlFromDatabase(user database.User, feedFollows []database.GetFeedFollowsForExportRow) *opml.Document {
+	document := opml.New(fmt.Sprintf("Subscriptions of %s", user.Name), time.Now())
+	for _, feedFollow := range feedFollows {
+		title := feedFollow.Name
+		if feedFollow.CustomTitle.Valid {
+			title = feedFollow.CustomTitle.String
+		}
+		document.Add(opml.Subscription{
+			Title:    title,
+			Url:      feedFollow.Url,
+			Category: feedFollow.CategoryName.String,
+		})
+	}
+	return document
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/opml"
+)
+
+const (
+	maxOpmlSize          = 5 << 20
+	maxOpmlSubscriptions = 1000
+)
+
+const (
+	OpmlImportPending   = "pending"
+	OpmlImportRunning   = "running"
+	OpmlImportCompleted = "completed"
+	OpmlImportFailed    = "failed"
+)
+
+// Statuses of each feed of an import.
+const (
+	OpmlResultPending         = "pending"
+	OpmlResultCreated         = "created"
+	OpmlResultFollowed        = "followed"
+	OpmlResultAlreadyFollowed = "already_followed"
+	OpmlResultFailed          = "failed"
+)
+
+// ImportOpmlParams reads an OPML document sent either as the "file" field of
+// a multipart form or as the raw request body.
+type ImportOpmlParams struct {
+	Subscriptions []opml.Subscription
+}
+
+func (b *ImportOpmlParams) Decode(r *http.Request) error {
+	var body io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxOpmlSize); err != nil {
+			return err
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return fmt.Errorf("file is required: %v", err)
+		}
+		defer file.Close()
+		body = file
+	}
+	dat, err := io.ReadAll(io.LimitReader(body, maxOpmlSize+1))
+	if err != nil {
+		return err
+	}
+	if len(dat) > maxOpmlSize {
+		return fmt.Errorf("OPML document must be at most %d bytes", maxOpmlSize)
+	}
+	document, err := opml.Parse(bytes.NewReader(dat))
+	if err != nil {
+		return fmt.Errorf("invalid OPML document: %v", err)
+	}
+	b.Subscriptions = document.Subscriptions()
+	for i := range b.Subscriptions {
+		b.Subscriptions[i].Title = truncateName(strings.TrimSpace(b.Subscriptions[i].Title))
+	}
+	return nil
+}
+
+func (b *ImportOpmlParams) Validate() error {
+	if len(b.Subscriptions) == 0 {
+		return errors.New("OPML document has no feed")
+	}
+	if len(b.Subscriptions) > maxOpmlSubscriptions {
+		return fmt.Errorf("OPML document must have at most %d feeds", maxOpmlSubscriptions)
+	}
+	return nil
+}
+
+type OpmlImportIDParams struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (b *OpmlImportIDParams) Decode(jobID string) error {
+	id, err := parseUUIDParam("job id", jobID)
+	if err != nil {
+		return err
+	}
+	b.ID = id
+	return nil
+}
+
+type OpmlImportResult struct {
+	Position int32  `json:"position"`
+	Title    string `json:"title"`
+	Url      string `json:"url"`
+	Category string `json:"category"`
+	Status   string `json:"status"`
+	Error    string `json:"error"`
+}
+
+type OpmlImport struct {
+	ID        uuid.UUID           `json:"id"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	UserID    uuid.UUID           `json:"user_id"`
+	Status    string              `json:"status"`
+	Total     int32               `json:"total"`
+	Error     string              `json:"error"`
+	Results   []*OpmlImportResult `json:"results"`
+}
+
+func NewOpmlImportFromDatabase(job database.OpmlImport, results []database.OpmlImportResult) *OpmlImport {
+	rs := make([]*OpmlImportResult, len(results))
+	for i, result := range results {
+		rs[i] = &OpmlImportResult{
+			Position: result.Position,
+			Title:    result.Title,
+			Url:      result.Url,
+			Category: result.Category.String,
+			Status:   result.Status,
+			Error:    result.Error.String,
+		}
+	}
+	return &OpmlImport{
+		ID:        job.ID,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+		UserID:    job.UserID,
+		Status:    job.Status,
+		Total:     job.Total,
+		Error:     job.Error.String,
+		Results:   rs,
+	}
+}
@@ -7,13 +7,20 @@ import (
 )
 
 type PaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+	Cursor string `json:"cursor"`
 }
 
+// Decode reads limit and offset, the offset being optional when paginating
+// with a cursor.
 func (p *PaginatedParams) Decode(r *http.Request) error {
 	limitString := r.URL.Query().Get("limit")
 	offsetString := r.URL.Query().Get("offset")
+	p.Cursor = r.URL.Query().Get("cursor")
+	if p.Cursor != "" && offsetString == "" {
+		offsetString = "0"
+	}
 	limit, err := strconv.Atoi(limitString)
 	if err != nil {
 		return err
@@ -37,9 +44,24 @@ func (p *PaginatedParams) Validate() error {
 	return nil
 }
 
+// NewPaginated wraps a page of a list of total items paginated with limit and
+// offset.
+func NewPaginated[T any](data []T, total int64, params PaginatedParams) Paginated[T] {
+	return Paginated[T]{
+		Data:    data,
+		Total:   int(total),
+		Offset:  int(params.Offset),
+		Limit:   int(params.Limit),
+		HasMore: int64(params.Offset)+int64(len(data)) < total,
+	}
+}
+
 type Paginated[T any] struct {
-	Data   []T `json:"data"`
-	Total  int `json:"total"`
-	Offset int `json:"offset"`
-	Limit  int `json:"limit"`
+	Data       []T    `json:"data"`
+	Total      int    `json:"total"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// parseUUIDParam parses a UUID received as a URL parameter.
+func parseUUIDParam(name string, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, fmt.Errorf("%s is required", name)
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return id, nil
+}
+
+// parseOptionalUUIDQuery parses an optional UUID received as a query parameter.
+func parseOptionalUUIDQuery(r *http.Request, name string) (uuid.NullUUID, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return uuid.NullUUID{}, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.NullUUID{}, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}, nil
+}
+
+// parseOptionalTimeQuery parses an optional RFC 3339 timestamp received as a
+// query parameter.
+func parseOptionalTimeQuery(r *http.Request, name string) (sql.NullTime, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return sql.NullTime{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+func nullUUIDToPointer(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+func nullTimeToPointer(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -1,33 +1,192 @@
 package models
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/postquery"
 )
 
+const (
+	PostStatusAll    = "all"
+	PostStatusRead   = "read"
+	PostStatusUnread = "unread"
+)
+
+const (
+	PostSortPublishedAt = "published_at"
+	PostSortCreatedAt   = "created_at"
+	SortAsc             = "asc"
+	SortDesc            = "desc"
+)
+
+const maxFeedIDsFilter = 100
+
+type GetPostsParams struct {
+	Status       string         `json:"status"`
+	CategoryID   uuid.NullUUID  `json:"category_id"`
+	Tag          sql.NullString `json:"tag"`
+	FeedIDs      []uuid.UUID    `json:"feed_id"`
+	Since        sql.NullTime   `json:"since"`
+	Until        sql.NullTime   `json:"until"`
+	Author       sql.NullString `json:"author"`
+	HasEnclosure sql.NullBool   `json:"has_enclosure"`
+	Sort         string         `json:"sort"`
+	Order        string         `json:"order"`
+}
+
+func (p *GetPostsParams) Decode(r *http.Request) error {
+	p.Status = r.URL.Query().Get("status")
+	if p.Status == "" {
+		p.Status = PostStatusAll
+	}
+	categoryID, err := parseOptionalUUIDQuery(r, "category_id")
+	if err != nil {
+		return err
+	}
+	p.CategoryID = categoryID
+	if tag := NormalizeTag(r.URL.Query().Get("tag")); tag != "" {
+		p.Tag = sql.NullString{String: tag, Valid: true}
+	}
+	// feed_id may be repeated or hold comma separated ids.
+	for _, value := range r.URL.Query()["feed_id"] {
+		for _, feedID := range strings.Split(value, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(feedID))
+			if err != nil {
+				return fmt.Errorf("invalid feed_id: %v", err)
+			}
+			p.FeedIDs = append(p.FeedIDs, id)
+		}
+	}
+	if p.Since, err = parseOptionalTimeQuery(r, "since"); err != nil {
+		return err
+	}
+	if p.Until, err = parseOptionalTimeQuery(r, "until"); err != nil {
+		return err
+	}
+	if author := strings.TrimSpace(r.URL.Query().Get("author")); author != "" {
+		p.Author = sql.NullString{String: author, Valid: true}
+	}
+	if value := r.URL.Query().Get("has_enclosure"); value != "" {
+		hasEnclosure, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid has_enclosure: %v", err)
+		}
+		p.HasEnclosure = sql.NullBool{Bool: hasEnclosure, Valid: true}
+	}
+	p.Sort = r.URL.Query().Get("sort")
+	if p.Sort == "" {
+		p.Sort = PostSortPublishedAt
+	}
+	p.Order = strings.ToLower(r.URL.Query().Get("order"))
+	if p.Order == "" {
+		p.Order = SortDesc
+	}
+	return nil
+}
+
+func (p *GetPostsParams) Validate() error {
+	if p.Status != PostStatusAll && p.Status != PostStatusRead && p.Status != PostStatusUnread {
+		return errors.New("status must be one of unread, read or all")
+	}
+	if len(p.FeedIDs) > maxFeedIDsFilter {
+		return fmt.Errorf("at most %d feed_id can be given", maxFeedIDsFilter)
+	}
+	if p.Since.Valid && p.Until.Valid && !p.Since.Time.Before(p.Until.Time) {
+		return errors.New("since must be before until")
+	}
+	if _, ok := postquery.SortColumns[p.Sort]; !ok {
+		return errors.New("sort must be one of published_at or created_at")
+	}
+	if p.Order != SortAsc && p.Order != SortDesc {
+		return errors.New("order must be one of asc or desc")
+	}
+	return nil
+}
+
+// UsesFilterQuery reports whether the filters or the sort order need the
+// dynamic postquery query rather than GetPostsForUser.
+func (p *GetPostsParams) UsesFilterQuery() bool {
+	return len(p.FeedIDs) > 0 || p.Since.Valid || p.Until.Valid || p.Author.Valid || p.HasEnclosure.Valid ||
+		p.Sort != PostSortPublishedAt || p.Order != SortDesc
+}
+
+func (p *GetPostsParams) FilterQuery(userID uuid.UUID) postquery.Params {
+	return postquery.Params{
+		UserID:       userID,
+		Status:       p.Status,
+		CategoryID:   p.CategoryID,
+		Tag:          p.Tag,
+		FeedIDs:      p.FeedIDs,
+		Since:        p.Since,
+		Until:        p.Until,
+		Author:       p.Author,
+		HasEnclosure: p.HasEnclosure,
+		Sort:         p.Sort,
+		Ascending:    p.Order == SortAsc,
+	}
+}
+
+// SortKey identifies the sort order, so a cursor is only used with the order
+// it was made for.
+func (p *GetPostsParams) SortKey() string {
+	return p.Sort + ":" + p.Order
+}
+
+// Cursor returns the cursor of a post in the list sorted by these params.
+func (p *GetPostsParams) Cursor(post *Post, direction string) *PostCursor {
+	value := post.PublishedAt
+	if p.Sort == PostSortCreatedAt {
+		value = post.CreatedAt
+	}
+	return &PostCursor{
+		Time:      value,
+		ID:        post.ID,
+		Direction: direction,
+		Sort:      p.SortKey(),
+	}
+}
+
 type Post struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"title"`
-	Url         string    `json:"url"`
-	Description string    `json:"description"`
-	PublishedAt time.Time `json:"published_at"`
-	FeedID      uuid.UUID `json:"feed_id"`
+	ID            uuid.UUID `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Title         string    `json:"title"`
+	Url           string    `json:"url"`
+	Description   string    `json:"description"`
+	PublishedAt   time.Time `json:"published_at"`
+	FeedID        uuid.UUID `json:"feed_id"`
+	Content       string    `json:"content"`
+	EnclosureUrl  string    `json:"enclosure_url"`
+	EnclosureType string    `json:"enclosure_type"`
+	CanonicalUrl  string    `json:"canonical_url"`
+	Truncated     bool      `json:"truncated"`
+	Author        string    `json:"author"`
 }
 
 func NewPostFromDatabase(post database.Post) *Post {
 	return &Post{
-		ID:          post.ID,
-		CreatedAt:   post.CreatedAt,
-		UpdatedAt:   post.UpdatedAt,
-		Title:       post.Title,
-		Url:         post.Url,
-		Description: post.Description.String,
-		PublishedAt: post.PublishedAt,
-		FeedID:      post.FeedID,
+		ID:            post.ID,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+		Title:         post.Title,
+		Url:           post.Url,
+		Description:   post.Description.String,
+		PublishedAt:   post.PublishedAt,
+		FeedID:        post.FeedID,
+		Content:       post.Content.String,
+		EnclosureUrl:  post.EnclosureUrl.String,
+		EnclosureType: post.EnclosureType.String,
+		CanonicalUrl:  post.CanonicalUrl,
+		Truncated:     post.Truncated,
+		Author:        post.Author.String,
 	}
 }
 
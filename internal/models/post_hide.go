@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type PostHideParams struct {
+	PostID uuid.UUID `json:"post_id"`
+}
+
+func (b *PostHideParams) Decode(postID string) error {
+	id, err := parseUUIDParam("post id", postID)
+	if err != nil {
+		return err
+	}
+	b.PostID = id
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+type PostReadParams struct {
+	PostID uuid.UUID `json:"post_id"`
+}
+
+func (b *PostReadParams) Decode(postID string) error {
+	id, err := parseUUIDParam("post id", postID)
+	if err != nil {
+		return err
+	}
+	b.PostID = id
+	return nil
+}
+
+// MarkPostsAsReadParams selects the posts marked as read in bulk. At least one
+// of the scopes is required and they are combined when several are given.
+type MarkPostsAsReadParams struct {
+	FeedID       *uuid.UUID `json:"feed_id"`
+	CategoryID   *uuid.UUID `json:"category_id"`
+	Before       *time.Time `json:"before"`
+	BeforePostID *uuid.UUID `json:"before_post_id"`
+}
+
+func (b *MarkPostsAsReadParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *MarkPostsAsReadParams) Validate() error {
+	if b.FeedID == nil && b.CategoryID == nil && b.Before == nil && b.BeforePostID == nil {
+		return errors.New("one of feed_id, category_id, before or before_post_id is required")
+	}
+	return nil
+}
+
+type MarkPostsAsReadResult struct {
+	Affected int64 `json:"affected"`
+}
+
+type UnreadCount struct {
+	FeedID      uuid.UUID `json:"feed_id"`
+	UnreadCount int64     `json:"unread_count"`
+}
+
+func NewUnreadCountsFromDatabase(rows []database.GetUnreadCountsForUserRow) []*UnreadCount {
+	counts := make([]*UnreadCount, len(rows))
+	for i, row := range rows {
+		counts[i] = &UnreadCount{
+			FeedID:      row.FeedID,
+			UnreadCount: row.UnreadCount,
+		}
+	}
+	return counts
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+type PostStarParams struct {
+	PostID uuid.UUID `json:"post_id"`
+}
+
+func (b *PostStarParams) Decode(postID string) error {
+	id, err := parseUUIDParam("post id", postID)
+	if err != nil {
+		return err
+	}
+	b.PostID = id
+	return nil
+}
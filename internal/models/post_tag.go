@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+const (
+	maxTagsPerPost = 50
+	maxTagLength   = 64
+)
+
+// NormalizeTag trims and lowercases a tag so "Go" and " go " are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+type SetPostTagsParams struct {
+	PostID uuid.UUID `json:"-"`
+	Tags   []string  `json:"tags"`
+}
+
+func (b *SetPostTagsParams) Decode(postID string, r *http.Request) error {
+	id, err := parseUUIDParam("post id", postID)
+	if err != nil {
+		return err
+	}
+	b.PostID = id
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate normalizes and deduplicates the tags.
+func (b *SetPostTagsParams) Validate() error {
+	if b.Tags == nil {
+		return errors.New("tags is required")
+	}
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, tag := range b.Tags {
+		tag = NormalizeTag(tag)
+		if tag == "" {
+			return errors.New("tags must not be empty")
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return fmt.Errorf("tags must be at most %d characters long", maxTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxTagsPerPost {
+		return fmt.Errorf("a post can have at most %d tags", maxTagsPerPost)
+	}
+	sort.Strings(tags)
+	b.Tags = tags
+	return nil
+}
+
+type PostTags struct {
+	PostID uuid.UUID `json:"post_id"`
+	Tags   []string  `json:"tags"`
+}
+
+type Tag struct {
+	Tag       string `json:"tag"`
+	PostCount int64  `json:"post_count"`
+}
+
+func NewTagsFromDatabase(rows []database.GetTagsForUserRow) []*Tag {
+	tags := make([]*Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = &Tag{
+			Tag:       row.Tag,
+			PostCount: row.PostCount,
+		}
+	}
+	return tags
+}
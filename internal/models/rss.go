@@ -4,29 +4,68 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/urls"
 )
 
 type RSSFeed struct {
 	XMLName xml.Name `xml:"rss"`
 	Version string   `xml:"version,attr"`
+	XMLBase string   `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Channel struct {
+		XMLBase     string    `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 		Title       string    `xml:"title"`
 		Link        string    `xml:"link"`
 		Description string    `xml:"description"`
 		Language    string    `xml:"language"`
+		Image       RSSImage  `xml:"image"`
+		AtomIcon    string    `xml:"http://www.w3.org/2005/Atom icon"`
+		AtomLogo    string    `xml:"http://www.w3.org/2005/Atom logo"`
 		Items       []RSSItem `xml:"item"`
 	} `xml:"channel"`
 }
 
+type RSSImage struct {
+	Url string `xml:"url"`
+}
+
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	XMLBase     string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	PubDate     string       `xml:"pubDate"`
+	Author      string       `xml:"author"`
+	Creator     string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Enclosure   RSSEnclosure `xml:"enclosure"`
+}
+
+type RSSEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// AuthorName returns the name of the item author, taken from dc:creator or
+// from <author>, whose "email (Name)" form is reduced to the name.
+func (item *RSSItem) AuthorName() string {
+	if creator := strings.TrimSpace(item.Creator); creator != "" {
+		return creator
+	}
+	author := strings.TrimSpace(item.Author)
+	if open := strings.Index(author, "("); open > 0 && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[open+1 : len(author)-1]); name != "" {
+			return name
+		}
+	}
+	return author
 }
 
 func (b *RSSFeed) Validate() error {
@@ -36,7 +75,52 @@ func (b *RSSFeed) Validate() error {
 	return nil
 }
 
+// IconURLs returns the candidate icon URLs advertised by the feed, in order of
+// preference: the Atom icon, the RSS channel image and the Atom logo.
+func (b *RSSFeed) IconURLs() []string {
+	urls := []string{}
+	for _, u := range []string{b.Channel.AtomIcon, b.Channel.Image.Url, b.Channel.AtomLogo} {
+		u = strings.TrimSpace(u)
+		if u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// ResolveURLs resolves the item links, enclosure URLs and the href/src
+// attributes of item descriptions against the item and channel xml:base, the
+// channel link and the feed URL, so relative and protocol-relative URLs are
+// stored as absolute ones.
+func (b *RSSFeed) ResolveURLs(feedUrl string) {
+	channelBase := []string{feedUrl, b.XMLBase, b.Channel.XMLBase}
+	b.Channel.Link = urls.Resolve(urls.Base(channelBase...), b.Channel.Link)
+	if b.XMLBase == "" && b.Channel.XMLBase == "" {
+		// Without an explicit xml:base, items are relative to the site rather
+		// than to the feed document.
+		channelBase = append(channelBase, b.Channel.Link)
+	}
+	for i := range b.Channel.Items {
+		item := &b.Channel.Items[i]
+		base := urls.Base(append(channelBase, item.XMLBase)...)
+		item.Link = strings.TrimSpace(urls.Resolve(base, item.Link))
+		item.Enclosure.Url = strings.TrimSpace(urls.Resolve(base, item.Enclosure.Url))
+		item.Description = urls.ResolveHTML(base, item.Description)
+	}
+}
+
+// DefaultMaxFeedSize is the largest feed body read when validating a feed.
+const DefaultMaxFeedSize = 10 << 20
+
+var ErrFeedTooLarge = errors.New("RSS feed is too large")
+
 func GetRSSFeedFromURL(url string) (RSSFeed, error) {
+	return GetRSSFeedFromURLWithLimit(url, DefaultMaxFeedSize)
+}
+
+// GetRSSFeedFromURLWithLimit fetches and parses a feed, failing when its body
+// is larger than maxSize bytes.
+func GetRSSFeedFromURLWithLimit(url string, maxSize int64) (RSSFeed, error) {
 	client := &http.Client{
 		Timeout: 200 * time.Millisecond,
 	}
@@ -49,18 +133,127 @@ func GetRSSFeedFromURL(url string) (RSSFeed, error) {
 		return RSSFeed{}, fmt.Errorf("URL returned status code: %d", resp.StatusCode)
 	}
 	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(strings.ToLower(contentType), "xml") &&
-		!strings.Contains(strings.ToLower(contentType), "rss") &&
-		!strings.Contains(strings.ToLower(contentType), "atom") {
+	if !isFeedContentType(contentType) {
 		return RSSFeed{}, fmt.Errorf("URL does not appear to be an RSS feed (content-type: %s)", contentType)
 	}
-	dat, err := io.ReadAll(resp.Body)
+	dat, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
 	if err != nil {
 		return RSSFeed{}, fmt.Errorf("failed to read RSS feed: %v", err)
 	}
+	if int64(len(dat)) > maxSize {
+		return RSSFeed{}, fmt.Errorf("%w: larger than %d bytes", ErrFeedTooLarge, maxSize)
+	}
 	rssFeed := RSSFeed{}
 	if err := xml.Unmarshal(dat, &rssFeed); err != nil {
 		return RSSFeed{}, fmt.Errorf("failed to unmarshal XML: %v", err)
 	}
 	return rssFeed, nil
 }
+
+func isFeedContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.Contains(contentType, "xml") ||
+		strings.Contains(contentType, "rss") ||
+		strings.Contains(contentType, "atom")
+}
+
+const (
+	discoveryTimeout = 5 * time.Second
+	maxDiscoverySize = 1 << 20
+)
+
+var (
+	htmlLinkTag   = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	htmlAttribute = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>"']+))`)
+)
+
+// DiscoverFeedURL returns the URL of the feed behind pageUrl: pageUrl itself
+// when it serves a feed, or the first RSS feed advertised by the
+// <link rel="alternate"> tags of the HTML page.
+func DiscoverFeedURL(pageUrl string) (string, error) {
+	client := &http.Client{
+		Timeout: discoveryTimeout,
+	}
+	resp, err := client.Get(pageUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch URL: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("URL returned status code: %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if isFeedContentType(contentType) {
+		return pageUrl, nil
+	}
+	if !strings.Contains(strings.ToLower(contentType), "html") {
+		return "", fmt.Errorf("URL is neither a feed nor an HTML page (content-type: %s)", contentType)
+	}
+	dat, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoverySize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read page: %v", err)
+	}
+	base := urls.Base(pageUrl)
+	for _, attrs := range linkTags(string(dat)) {
+		if !slices.Contains(strings.Fields(strings.ToLower(attrs["rel"])), "alternate") {
+			continue
+		}
+		if strings.ToLower(strings.TrimSpace(attrs["type"])) != "application/rss+xml" {
+			continue
+		}
+		if href := strings.TrimSpace(html.UnescapeString(attrs["href"])); href != "" {
+			return urls.Resolve(base, href), nil
+		}
+	}
+	return "", errors.New("no feed found at URL")
+}
+
+// DiscoverIconURLs returns the icons advertised by the <link rel="icon">,
+// <link rel="shortcut icon"> and <link rel="apple-touch-icon"> tags of the
+// HTML page at pageUrl.
+func DiscoverIconURLs(pageUrl string) ([]string, error) {
+	client := &http.Client{
+		Timeout: discoveryTimeout,
+	}
+	resp, err := client.Get(pageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("URL returned status code: %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.Contains(strings.ToLower(contentType), "html") {
+		return nil, fmt.Errorf("URL is not an HTML page (content-type: %s)", contentType)
+	}
+	dat, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoverySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %v", err)
+	}
+	base := urls.Base(pageUrl)
+	icons := []string{}
+	for _, attrs := range linkTags(string(dat)) {
+		rel := strings.Fields(strings.ToLower(attrs["rel"]))
+		if !slices.Contains(rel, "icon") && !slices.Contains(rel, "apple-touch-icon") {
+			continue
+		}
+		if href := strings.TrimSpace(html.UnescapeString(attrs["href"])); href != "" {
+			icons = append(icons, urls.Resolve(base, href))
+		}
+	}
+	return icons, nil
+}
+
+// linkTags returns the attributes of the <link> tags of an HTML document,
+// keyed by their lowercased name.
+func linkTags(document string) []map[string]string {
+	tags := []map[string]string{}
+	for _, tag := range htmlLinkTag.FindAllString(document, -1) {
+		attrs := map[string]string{}
+		for _, match := range htmlAttribute.FindAllStringSubmatch(tag[len("<link"):], -1) {
+			attrs[strings.ToLower(match[1])] = match[2] + match[3] + match[4]
+		}
+		tags = append(tags, attrs)
+	}
+	return tags
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+type SearchPostsParams struct {
+	Query   string `json:"q"`
+	TsQuery string `json:"-"`
+}
+
+func (p *SearchPostsParams) Decode(r *http.Request) error {
+	p.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+	return nil
+}
+
+func (p *SearchPostsParams) Validate() error {
+	if p.Query == "" {
+		return errors.New("q is required")
+	}
+	p.TsQuery = BuildTsQuery(p.Query)
+	if p.TsQuery == "" {
+		return errors.New("q must contain at least one word")
+	}
+	return nil
+}
+
+// BuildTsQuery turns a user search into a to_tsquery expression: quoted
+// phrases match adjacent words, a trailing * makes a prefix match and every
+// term is required. Only letters and digits are kept from the input, so the
+// result never contains tsquery syntax written by the user.
+//
+// Example: `"machine learning" kube*` becomes `(machine <-> learning) & kube:*`.
+func BuildTsQuery(query string) string {
+	terms := []string{}
+	for i, part := range strings.Split(query, `"`) {
+		// Odd parts are between quotes.
+		if i%2 == 1 {
+			if words := tsQueryWords(part); len(words) > 0 {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			prefix := strings.HasSuffix(field, "*")
+			words := tsQueryWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			if prefix {
+				words[len(words)-1] += ":*"
+			}
+			if len(words) == 1 {
+				terms = append(terms, words[0])
+			} else {
+				terms = append(terms, "("+strings.Join(words, " <-> ")+")")
+			}
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+func tsQueryWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+const (
+	snippetStartSel = "<mark>"
+	snippetStopSel  = "</mark>"
+)
+
+// escapeSnippet makes a search snippet safe to insert as HTML. The query
+// strips the tags of the post before highlighting it, and the text left,
+// which may still hold entities or stray angle brackets, is escaped here so
+// that the <mark> delimiters added by ts_headline are the only markup.
+func escapeSnippet(snippet string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(snippet, snippetStartSel) {
+		if i > 0 {
+			b.WriteString(snippetStartSel)
+		}
+		for j, text := range strings.Split(part, snippetStopSel) {
+			if j > 0 {
+				b.WriteString(snippetStopSel)
+			}
+			b.WriteString(html.EscapeString(html.UnescapeString(text)))
+		}
+	}
+	return b.String()
+}
+
+type SearchResult struct {
+	*Post
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func NewSearchResultsFromDatabase(rows []database.SearchPostsForUserRow) []*SearchResult {
+	results := make([]*SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = &SearchResult{
+			Post: NewPostFromDatabase(database.Post{
+				ID:            row.ID,
+				CreatedAt:     row.CreatedAt,
+				UpdatedAt:     row.UpdatedAt,
+				Title:         row.Title,
+				Url:           row.Url,
+				Description:   row.Description,
+				PublishedAt:   row.PublishedAt,
+				FeedID:        row.FeedID,
+				Content:       row.Content,
+				EnclosureUrl:  row.EnclosureUrl,
+				EnclosureType: row.EnclosureType,
+				CanonicalUrl:  row.CanonicalUrl,
+				Truncated:     row.Truncated,
+				Author:        row.Author,
+			}),
+			Rank:    row.Rank,
+			Snippet: escapeSnippet(row.Snippet),
+		}
+	}
+	return results
+}
@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestEscapeSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"plain text", "a <mark>match</mark> here", "a <mark>match</mark> here"},
+		{"stray angle brackets are escaped", "1 < 2 <mark>go</mark> >", "1 &lt; 2 <mark>go</mark> &gt;"},
+		{"script is escaped", "<script>alert(1)</script> <mark>go</mark>", "&lt;script&gt;alert(1)&lt;/script&gt; <mark>go</mark>"},
+		{"entities are not escaped twice", "Tom &amp; Jerry <mark>cat</mark>", "Tom &amp; Jerry <mark>cat</mark>"},
+		{"encoded tags stay text", "&lt;b&gt; <mark>bold</mark>", "&lt;b&gt; <mark>bold</mark>"},
+		{"quotes are escaped", `say "<mark>hi</mark>"`, "say &#34;<mark>hi</mark>&#34;"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeSnippet(tt.snippet); got != tt.want {
+				t.Errorf("escapeSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+// maxFeedNameLength bounds the names taken from OPML documents and feed
+// channels, which also become custom titles.
+const maxFeedNameLength = maxCustomTitleLength
+
+// truncateName cuts a name to maxFeedNameLength characters.
+func truncateName(name string) string {
+	if utf8.RuneCountInString(name) <= maxFeedNameLength {
+		return name
+	}
+	return string([]rune(name)[:maxFeedNameLength])
+}
+
+type CreateSubscriptionParams struct {
+	Url          string `json:"url"`
+	Name         string `json:"name"`
+	CanonicalUrl string `json:"-"`
+}
+
+func (b *CreateSubscriptionParams) Decode(r *http.Request) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks the URL and computes its canonical form, used to find an
+// existing feed before fetching anything.
+func (b *CreateSubscriptionParams) Validate() error {
+	b.Url = strings.TrimSpace(b.Url)
+	b.Name = truncateName(strings.TrimSpace(b.Name))
+	canonicalUrl, err := canonicalizeFeedUrl(b.Url)
+	if err != nil {
+		return err
+	}
+	b.CanonicalUrl = canonicalUrl
+	return nil
+}
+
+// Discover replaces the URL by the one of the feed it serves or advertises,
+// validates that feed and names it after its channel unless a name was given.
+func (b *CreateSubscriptionParams) Discover() error {
+	feedUrl, err := DiscoverFeedURL(b.Url)
+	if err != nil {
+		return fmt.Errorf("invalid RSS URL: %v", err)
+	}
+	canonicalUrl, rssFeed, err := validateFeedUrl(feedUrl)
+	if err != nil {
+		return err
+	}
+	b.Url = feedUrl
+	b.CanonicalUrl = canonicalUrl
+	if b.Name == "" {
+		b.Name = truncateName(strings.TrimSpace(rssFeed.Channel.Title))
+	}
+	if b.Name == "" {
+		if u, err := url.Parse(feedUrl); err == nil {
+			b.Name = u.Hostname()
+		}
+	}
+	return nil
+}
+
+type Subscription struct {
+	Feed       *Feed       `json:"feed"`
+	FeedFollow *FeedFollow `json:"feed_follow"`
+}
+
+func NewSubscriptionFromDatabase(feed database.Feed, feedFollow database.FeedFollow) *Subscription {
+	return &Subscription{
+		Feed:       NewFeedFromDatabase(feed),
+		FeedFollow: NewFeedFollowFromDatabase(feedFollow),
+	}
+}
@@ -36,6 +36,7 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	Name      string    `json:"name"`
 	ApiKey    string    `json:"api_key"`
+	IsAdmin   bool      `json:"is_admin"`
 }
 
 func NewUserFromDatabase(user database.User) *User {
@@ -45,5 +46,6 @@ func NewUserFromDatabase(user database.User) *User {
 		UpdatedAt: user.UpdatedAt,
 		Name:      user.Name,
 		ApiKey:    user.ApiKey,
+		IsAdmin:   user.IsAdmin,
 	}
 }
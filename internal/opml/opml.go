@@ -0,0 +1,136 @@
+// Package opml reads and writes the OPML documents used to move subscriptions
+// between feed readers.
+package opml
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a feed, when XMLUrl is set, or a folder of outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLUrl   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLUrl  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Subscription is a feed of the document with the folder it belongs to.
+type Subscription struct {
+	Title    string
+	Url      string
+	Category string
+}
+
+func Parse(r io.Reader) (*Document, error) {
+	document := &Document{}
+	if err := xml.NewDecoder(r).Decode(document); err != nil {
+		return nil, err
+	}
+	if document.XMLName.Local != "opml" {
+		return nil, errors.New("not an OPML document")
+	}
+	return document, nil
+}
+
+// Subscriptions lists the feeds of the document in order. The category of a
+// feed is the title of its closest enclosing folder.
+func (d *Document) Subscriptions() []Subscription {
+	subscriptions := []Subscription{}
+	var walk func(outlines []Outline, category string)
+	walk = func(outlines []Outline, category string) {
+		for _, outline := range outlines {
+			title := strings.TrimSpace(outline.Title)
+			if title == "" {
+				title = strings.TrimSpace(outline.Text)
+			}
+			if url := strings.TrimSpace(outline.XMLUrl); url != "" {
+				subscriptions = append(subscriptions, Subscription{
+					Title:    title,
+					Url:      url,
+					Category: category,
+				})
+				continue
+			}
+			if title == "" {
+				title = category
+			}
+			walk(outline.Outlines, title)
+		}
+	}
+	walk(d.Body.Outlines, "")
+	return subscriptions
+}
+
+// New returns an empty OPML 2.0 document.
+func New(title string, createdAt time.Time) *Document {
+	return &Document{
+		Version: "2.0",
+		Head: Head{
+			Title:       title,
+			DateCreated: createdAt.UTC().Format(time.RFC1123Z),
+		},
+	}
+}
+
+// Add appends a feed to the document, inside the folder named after its
+// category, created on first use.
+func (d *Document) Add(subscription Subscription) {
+	feed := Outline{
+		Text:   subscription.Title,
+		Title:  subscription.Title,
+		Type:   "rss",
+		XMLUrl: subscription.Url,
+	}
+	if subscription.Category == "" {
+		d.Body.Outlines = append(d.Body.Outlines, feed)
+		return
+	}
+	for i := range d.Body.Outlines {
+		folder := &d.Body.Outlines[i]
+		if folder.XMLUrl == "" && folder.Text == subscription.Category {
+			folder.Outlines = append(folder.Outlines, feed)
+			return
+		}
+	}
+	d.Body.Outlines = append(d.Body.Outlines, Outline{
+		Text:     subscription.Category,
+		Title:    subscription.Category,
+		Outlines: []Outline{feed},
+	})
+}
+
+// Write encodes the document with its XML declaration.
+func (d *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
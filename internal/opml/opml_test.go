@@ -0,0 +1,161 @@
+package opml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSubscriptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []Subscription
+	}{
+		{
+			name: "flat list",
+			document: `<?xml version="1.0"?>
+<opml version="2.0"><head><title>Feeds</title></head><body>
+  <outline text="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom"/>
+  <outline text="Example" xmlUrl="https://example.com/rss"/>
+</body></opml>`,
+			want: []Subscription{
+				{Title: "Go Blog", Url: "https://go.dev/blog/feed.atom"},
+				{Title: "Example", Url: "https://example.com/rss"},
+			},
+		},
+		{
+			name: "folders give the category",
+			document: `<opml version="1.0"><body>
+  <outline text="Tech">
+    <outline text="Go Blog" xmlUrl="https://go.dev/blog/feed.atom"/>
+    <outline text="Nested">
+      <outline text="Deep" xmlUrl="https://deep.example.com/rss"/>
+    </outline>
+  </outline>
+  <outline text="Top" xmlUrl="https://top.example.com/rss"/>
+</body></opml>`,
+			want: []Subscription{
+				{Title: "Go Blog", Url: "https://go.dev/blog/feed.atom", Category: "Tech"},
+				{Title: "Deep", Url: "https://deep.example.com/rss", Category: "Nested"},
+				{Title: "Top", Url: "https://top.example.com/rss"},
+			},
+		},
+		{
+			name: "title attribute wins over text and spaces are trimmed",
+			document: `<opml version="2.0"><body>
+  <outline text="text" title=" Title " xmlUrl=" https://example.com/rss "/>
+  <outline text=" Only text " xmlUrl="https://example.com/other"/>
+</body></opml>`,
+			want: []Subscription{
+				{Title: "Title", Url: "https://example.com/rss"},
+				{Title: "Only text", Url: "https://example.com/other"},
+			},
+		},
+		{
+			name: "untitled folder keeps the enclosing category",
+			document: `<opml version="2.0"><body>
+  <outline text="News"><outline text=""><outline text="A" xmlUrl="https://a.example.com/rss"/></outline></outline>
+</body></opml>`,
+			want: []Subscription{
+				{Title: "A", Url: "https://a.example.com/rss", Category: "News"},
+			},
+		},
+		{
+			name: "outlines without a feed url are skipped",
+			document: `<opml version="2.0"><body>
+  <outline text="Website" htmlUrl="https://example.com/"/>
+  <outline text="Empty folder"></outline>
+</body></opml>`,
+			want: []Subscription{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := Parse(strings.NewReader(tt.document))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := document.Subscriptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subscriptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{"empty", ""},
+		{"not xml", "subscriptions"},
+		{"other root element", `<rss version="2.0"><channel></channel></rss>`},
+		{"unclosed", `<opml version="2.0"><body><outline text="A">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.document)); err == nil {
+				t.Errorf("Parse(%q) error = nil, want an error", tt.document)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))
+	document := New("Ada's subscriptions", createdAt)
+	document.Add(Subscription{Title: "Go Blog", Url: "https://go.dev/blog/feed.atom", Category: "Tech"})
+	document.Add(Subscription{Title: "Top & Co", Url: "https://top.example.com/rss?a=1&b=2"})
+	document.Add(Subscription{Title: "Rust", Url: "https://blog.rust-lang.org/feed.xml", Category: "Tech"})
+	var b strings.Builder
+	if err := document.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Ada&#39;s subscriptions</title>
+    <dateCreated>Wed, 01 May 2024 10:00:00 +0000</dateCreated>
+  </head>
+  <body>
+    <outline text="Tech" title="Tech">
+      <outline text="Go Blog" title="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom"></outline>
+      <outline text="Rust" title="Rust" type="rss" xmlUrl="https://blog.rust-lang.org/feed.xml"></outline>
+    </outline>
+    <outline text="Top &amp; Co" title="Top &amp; Co" type="rss" xmlUrl="https://top.example.com/rss?a=1&amp;b=2"></outline>
+  </body>
+</opml>
+`
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	subscriptions := []Subscription{
+		{Title: "Go Blog", Url: "https://go.dev/blog/feed.atom", Category: "Tech"},
+		{Title: "Rust", Url: "https://blog.rust-lang.org/feed.xml", Category: "Tech"},
+		{Title: "Café <news>", Url: "https://example.com/rss?lang=fr&utm=x", Category: "Français"},
+		{Title: "Uncategorized", Url: "https://top.example.com/rss"},
+	}
+	document := New("Subscriptions", time.Now())
+	for _, subscription := range subscriptions {
+		document.Add(subscription)
+	}
+	var b strings.Builder
+	if err := document.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	parsed, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Head.Title != "Subscriptions" || parsed.Version != "2.0" {
+		t.Errorf("Parse() head = %+v, version %q", parsed.Head, parsed.Version)
+	}
+	if got := parsed.Subscriptions(); !reflect.DeepEqual(got, subscriptions) {
+		t.Errorf("Subscriptions() = %+v, want %+v", got, subscriptions)
+	}
+}
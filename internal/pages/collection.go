@@ -0,0 +1,66 @@
+// Package pages renders the HTML pages served to visitors without an
+// account.
+package pages
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+type CollectionPost struct {
+	Title       string
+	Url         string
+	Description string
+	Author      string
+	PublishedAt time.Time
+}
+
+type CollectionPage struct {
+	Title       string
+	Description string
+	Author      string
+	RSSUrl      string
+	Posts       []CollectionPost
+}
+
+// collectionTemplate escapes every value, post descriptions included, since
+// they come from third-party feeds.
+var collectionTemplate = template.Must(template.New("collection").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Title}}</title>
+  <link rel="alternate" type="application/rss+xml" title="{{.Title}}" href="{{.RSSUrl}}">
+  <style>
+    body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+    header p, li small { color: #666; }
+    ul { list-style: none; padding: 0; }
+    li { margin-bottom: 1.5rem; }
+  </style>
+</head>
+<body>
+  <header>
+    <h1>{{.Title}}</h1>
+    {{if .Description}}<p>{{.Description}}</p>{{end}}
+    <p>Curated by {{.Author}} &middot; <a href="{{.RSSUrl}}">RSS</a></p>
+  </header>
+  <ul>
+    {{range .Posts}}
+    <li>
+      <a href="{{.Url}}">{{.Title}}</a>
+      <br><small>{{if .Author}}{{.Author}} &middot; {{end}}{{.PublishedAt.Format "January 2, 2006"}}</small>
+      {{if .Description}}<p>{{.Description}}</p>{{end}}
+    </li>
+    {{else}}
+    <li>This collection has no posts yet.</li>
+    {{end}}
+  </ul>
+</body>
+</html>
+`))
+
+func RenderCollection(w io.Writer, page CollectionPage) error {
+	return collectionTemplate.Execute(w, page)
+}
@@ -0,0 +1,203 @@
+// Package postquery lists the posts of a user with the filters sqlc queries
+// cannot express. The queries are assembled at runtime from the filters in
+// use: every value is sent as a placeholder argument and the sort column is
+// picked from a fixed list, so no user input ever becomes part of the SQL
+// text.
+package postquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+// postColumns must list the columns of posts in the order of postFields.
+const postColumns = "p.id, p.created_at, p.updated_at, p.title, p.url, p.description, p.published_at, p.feed_id, p.content, p.enclosure_url, p.enclosure_type, p.canonical_url, p.truncated, p.author"
+
+const filterPostsFrom = `
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id`
+
+// filterPostsVisible excludes the posts the user hid or muted, as GetPostsForUser does.
+const filterPostsVisible = `post_visible_to_user(p, ff.user_id)`
+
+// SortColumns maps the accepted sort keys to their column.
+var SortColumns = map[string]string{
+	"published_at": "p.published_at",
+	"created_at":   "p.created_at",
+}
+
+type Params struct {
+	UserID       uuid.UUID
+	Status       string
+	CategoryID   uuid.NullUUID
+	Tag          sql.NullString
+	FeedIDs      []uuid.UUID
+	Since        sql.NullTime
+	Until        sql.NullTime
+	Author       sql.NullString
+	HasEnclosure sql.NullBool
+	Sort         string
+	Ascending    bool
+	// CursorTime and CursorID, the sort value and id of a post, restrict the
+	// results to the posts after it in the sort order.
+	CursorTime sql.NullTime
+	CursorID   uuid.NullUUID
+	Limit      int32
+	Offset     int32
+}
+
+type builder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// where adds a condition, replacing each %s of the format by the placeholder
+// of the matching value.
+func (b *builder) where(format string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, value := range values {
+		b.args = append(b.args, value)
+		placeholders[i] = fmt.Sprintf("$%d", len(b.args))
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (b *builder) whereClause() string {
+	return "\nWHERE " + strings.Join(b.conditions, "\n    AND ")
+}
+
+func newBuilder(arg Params) *builder {
+	b := &builder{}
+	b.where("ff.user_id = %s", arg.UserID)
+	switch arg.Status {
+	case "read":
+		b.where("pr.post_id IS NOT NULL")
+	case "unread":
+		b.where("pr.post_id IS NULL")
+	}
+	if arg.CategoryID.Valid {
+		b.where("ff.category_id = %s", arg.CategoryID.UUID)
+	}
+	if arg.Tag.Valid {
+		b.where("EXISTS (SELECT 1 FROM post_tags pt WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = %s)", arg.Tag.String)
+	}
+	if len(arg.FeedIDs) == 0 {
+		// Feeds hidden from the timeline only show up when asked for.
+		b.conditions = append(b.conditions, "NOT ff.hide_from_timeline")
+	} else {
+		feedIDs := make([]string, len(arg.FeedIDs))
+		for i, feedID := range arg.FeedIDs {
+			feedIDs[i] = feedID.String()
+		}
+		b.where("p.feed_id = ANY(%s::uuid[])", pq.Array(feedIDs))
+	}
+	if arg.Since.Valid {
+		b.where("p.published_at >= %s", arg.Since.Time)
+	}
+	if arg.Until.Valid {
+		b.where("p.published_at < %s", arg.Until.Time)
+	}
+	if arg.Author.Valid {
+		b.where("lower(p.author) = lower(%s)", arg.Author.String)
+	}
+	if arg.HasEnclosure.Valid {
+		b.where("(p.enclosure_url IS NOT NULL) = %s", arg.HasEnclosure.Bool)
+	}
+	b.conditions = append(b.conditions, filterPostsVisible)
+	return b
+}
+
+// selectQuery returns the query listing the posts matching the params and its
+// arguments.
+func selectQuery(arg Params) (string, []interface{}, error) {
+	column, ok := SortColumns[arg.Sort]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown sort column %q", arg.Sort)
+	}
+	direction, comparison := "DESC", "<"
+	if arg.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+	b := newBuilder(arg)
+	if arg.CursorTime.Valid && arg.CursorID.Valid {
+		b.where("("+column+", p.id) "+comparison+" (%s, %s)", arg.CursorTime.Time, arg.CursorID.UUID)
+	}
+	query := "SELECT " + postColumns + filterPostsFrom + b.whereClause() +
+		fmt.Sprintf("\nORDER BY %s %s, p.id %s", column, direction, direction)
+	b.args = append(b.args, arg.Limit, arg.Offset)
+	query += fmt.Sprintf("\nLIMIT $%d\nOFFSET $%d", len(b.args)-1, len(b.args))
+	return query, b.args, nil
+}
+
+// countQuery returns the query counting the posts matching the params, without
+// cursor nor pagination, and its arguments.
+func countQuery(arg Params) (string, []interface{}) {
+	b := newBuilder(arg)
+	return "SELECT COUNT(*)" + filterPostsFrom + b.whereClause(), b.args
+}
+
+// postFields returns the scan destinations of a post, in the order of
+// postColumns.
+func postFields(i *database.Post) []interface{} {
+	return []interface{}{
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		&i.EnclosureUrl,
+		&i.EnclosureType,
+		&i.CanonicalUrl,
+		&i.Truncated,
+		&i.Author,
+	}
+}
+
+// Posts returns the posts of the feeds followed by a user matching the
+// filters that are set, in the requested order.
+func Posts(ctx context.Context, db database.DBTX, arg Params) ([]database.Post, error) {
+	query, args, err := selectQuery(arg)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.Post
+	for rows.Next() {
+		var i database.Post
+		if err := rows.Scan(postFields(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Count counts the posts Posts would return without cursor nor pagination.
+func Count(ctx context.Context, db database.DBTX, arg Params) (int64, error) {
+	query, args := countQuery(arg)
+	row := db.QueryRowContext(ctx, query, args...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
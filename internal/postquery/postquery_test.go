@@ -0,0 +1,154 @@
+package postquery
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mellomaths/rss-aggregator/internal/database"
+)
+
+// columnName returns the column sqlc maps to a field name, e.g. FeedID to
+// feed_id.
+func columnName(field string) string {
+	field = strings.ReplaceAll(field, "ID", "Id")
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func TestPostColumnsMatchPostFields(t *testing.T) {
+	postType := reflect.TypeOf(database.Post{})
+	want := make([]string, postType.NumField())
+	for i := range want {
+		want[i] = "p." + columnName(postType.Field(i).Name)
+	}
+	if got := strings.Split(postColumns, ", "); !reflect.DeepEqual(got, want) {
+		t.Errorf("postColumns = %q, want %q", got, want)
+	}
+
+	var post database.Post
+	fields := postFields(&post)
+	if len(fields) != postType.NumField() {
+		t.Fatalf("postFields() returns %d fields, want %d", len(fields), postType.NumField())
+	}
+	postValue := reflect.ValueOf(&post).Elem()
+	for i, field := range fields {
+		if reflect.ValueOf(field).Pointer() != postValue.Field(i).Addr().Pointer() {
+			t.Errorf("postFields()[%d] is not a pointer to Post.%s", i, postType.Field(i).Name)
+		}
+	}
+}
+
+func TestSelectQuery(t *testing.T) {
+	userID := uuid.MustParse("2b0a3a8e-6c1e-4d55-8f5a-0c7f6f0c9d11")
+	feedID := uuid.MustParse("9d4b5a17-0c2a-4e7b-b1f5-1f2e3d4c5b6a")
+	cursorID := uuid.MustParse("5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9")
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		arg   Params
+		where []string
+		order string
+		args  []interface{}
+	}{
+		{
+			name:  "no filter",
+			arg:   Params{UserID: userID, Sort: "published_at", Limit: 20},
+			where: []string{"ff.user_id = $1", "NOT ff.hide_from_timeline", filterPostsVisible},
+			order: "p.published_at DESC, p.id DESC\nLIMIT $2\nOFFSET $3",
+			args:  []interface{}{userID, int32(20), int32(0)},
+		},
+		{
+			name: "status, tag and author",
+			arg: Params{
+				UserID: userID, Status: "unread", Tag: sql.NullString{String: "go", Valid: true},
+				Author: sql.NullString{String: "Ada", Valid: true}, Sort: "created_at", Ascending: true, Limit: 10, Offset: 30,
+			},
+			where: []string{
+				"ff.user_id = $1",
+				"pr.post_id IS NULL",
+				"EXISTS (SELECT 1 FROM post_tags pt WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = $2)",
+				"NOT ff.hide_from_timeline",
+				"lower(p.author) = lower($3)",
+				filterPostsVisible,
+			},
+			order: "p.created_at ASC, p.id ASC\nLIMIT $4\nOFFSET $5",
+			args:  []interface{}{userID, "go", "Ada", int32(10), int32(30)},
+		},
+		{
+			name: "feeds, dates, enclosure and cursor",
+			arg: Params{
+				UserID: userID, Status: "read", FeedIDs: []uuid.UUID{feedID},
+				Since: sql.NullTime{Time: since, Valid: true}, HasEnclosure: sql.NullBool{Bool: true, Valid: true},
+				Sort: "published_at", CursorTime: sql.NullTime{Time: since, Valid: true},
+				CursorID: uuid.NullUUID{UUID: cursorID, Valid: true}, Limit: 5,
+			},
+			where: []string{
+				"ff.user_id = $1",
+				"pr.post_id IS NOT NULL",
+				"p.feed_id = ANY($2::uuid[])",
+				"p.published_at >= $3",
+				"(p.enclosure_url IS NOT NULL) = $4",
+				filterPostsVisible,
+				"(p.published_at, p.id) < ($5, $6)",
+			},
+			order: "p.published_at DESC, p.id DESC\nLIMIT $7\nOFFSET $8",
+			args:  []interface{}{userID, pq.Array([]string{feedID.String()}), since, true, since, cursorID, int32(5), int32(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := selectQuery(tt.arg)
+			if err != nil {
+				t.Fatalf("selectQuery() error = %v", err)
+			}
+			want := "SELECT " + postColumns + filterPostsFrom +
+				"\nWHERE " + strings.Join(tt.where, "\n    AND ") + "\nORDER BY " + tt.order
+			if query != want {
+				t.Errorf("selectQuery() query =\n%s\nwant\n%s", query, want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("selectQuery() args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestSelectQueryUnknownSort(t *testing.T) {
+	if _, _, err := selectQuery(Params{Sort: "title; DROP TABLE posts"}); err == nil {
+		t.Error("selectQuery() accepted an unknown sort column")
+	}
+}
+
+func TestCountQuery(t *testing.T) {
+	userID := uuid.MustParse("2b0a3a8e-6c1e-4d55-8f5a-0c7f6f0c9d11")
+	query, args := countQuery(Params{
+		UserID:     userID,
+		Sort:       "published_at",
+		CursorTime: sql.NullTime{Time: time.Now(), Valid: true},
+		CursorID:   uuid.NullUUID{UUID: userID, Valid: true},
+		Limit:      20,
+	})
+	want := "SELECT COUNT(*)" + filterPostsFrom +
+		"\nWHERE ff.user_id = $1\n    AND NOT ff.hide_from_timeline\n    AND " + filterPostsVisible
+	if query != want {
+		t.Errorf("countQuery() query =\n%s\nwant\n%s", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{userID}) {
+		t.Errorf("countQuery() args = %#v, want only the user id", args)
+	}
+}
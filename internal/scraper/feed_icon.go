@@ -0,0 +1,171 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/models"
+)
+
+const (
+	maxIconSize         = 256 << 10
+	iconRefreshInterval = 7 * 24 * time.Hour
+	iconRequestTimeout  = 5 * time.Second
+	// After all the candidates of a feed failed, they are not fetched again
+	// before iconRetryDelay, doubled at each new failure up to iconMaxRetryDelay.
+	iconRetryDelay    = 24 * time.Hour
+	iconMaxRetryDelay = 30 * 24 * time.Hour
+)
+
+// allowedIconTypes lists the sniffed content types accepted as feed icons.
+// SVG is deliberately excluded since it may carry scripts.
+var allowedIconTypes = map[string]bool{
+	"image/png":                true,
+	"image/jpeg":               true,
+	"image/gif":                true,
+	"image/webp":               true,
+	"image/bmp":                true,
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+}
+
+func (s *RSSScraper) refreshFeedIcon(feed *database.Feed, rssFeed models.RSSFeed) {
+	updatedAt, err := s.Database.GetFeedIconUpdatedAt(context.Background(), feed.ID)
+	if err == nil && time.Since(updatedAt) < iconRefreshInterval {
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error getting icon of feed %v (%v): %v", feed.Name, feed.ID, err)
+		return
+	}
+	failure, err := s.Database.GetFeedIconFailure(context.Background(), feed.ID)
+	if err == nil && time.Now().Before(failure.RetryAt) {
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error getting icon failure of feed %v (%v): %v", feed.Name, feed.ID, err)
+		return
+	}
+	for _, candidate := range iconCandidates(feed.Url, rssFeed) {
+		contentType, data, err := fetchIcon(candidate)
+		if err != nil {
+			log.Printf("Error fetching icon %v for feed %v (%v): %v", candidate, feed.Name, feed.ID, err)
+			continue
+		}
+		hash := sha256.Sum256(data)
+		_, err = s.Database.UpsertFeedIcon(context.Background(), database.UpsertFeedIconParams{
+			FeedID:      feed.ID,
+			CreatedAt:   time.Now().UTC(),
+			UpdatedAt:   time.Now().UTC(),
+			SourceUrl:   candidate,
+			ContentType: contentType,
+			Data:        data,
+			Etag:        hex.EncodeToString(hash[:]),
+		})
+		if err != nil {
+			log.Printf("Error saving icon for feed %v (%v): %v", feed.Name, feed.ID, err)
+			return
+		}
+		if failure.Failures > 0 {
+			if err := s.Database.DeleteFeedIconFailure(context.Background(), feed.ID); err != nil {
+				log.Printf("Error clearing icon failure of feed %v (%v): %v", feed.Name, feed.ID, err)
+			}
+		}
+		return
+	}
+	failures := failure.Failures + 1
+	err = s.Database.UpsertFeedIconFailure(context.Background(), database.UpsertFeedIconFailureParams{
+		FeedID:   feed.ID,
+		Failures: failures,
+		RetryAt:  time.Now().UTC().Add(iconBackoff(failures)),
+	})
+	if err != nil {
+		log.Printf("Error recording icon failure of feed %v (%v): %v", feed.Name, feed.ID, err)
+	}
+}
+
+// iconBackoff returns the delay before looking for the icon of a feed again
+// after the given number of consecutive failures.
+func iconBackoff(failures int32) time.Duration {
+	delay := iconRetryDelay
+	for i := int32(1); i < failures && delay < iconMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, iconMaxRetryDelay)
+}
+
+// iconCandidates resolves the icons advertised by the feed, then the icons
+// declared by the <link> tags of its site, and falls back to the favicon of
+// the site and of the feed host.
+func iconCandidates(feedUrl string, rssFeed models.RSSFeed) []string {
+	base, err := url.Parse(feedUrl)
+	if err != nil {
+		return []string{}
+	}
+	candidates := []string{}
+	seen := map[string]bool{}
+	add := func(ref *url.URL, raw string) {
+		u, err := ref.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+		candidates = append(candidates, u.String())
+	}
+	for _, icon := range rssFeed.IconURLs() {
+		add(base, icon)
+	}
+	site := base.ResolveReference(&url.URL{Path: "/"})
+	if link, err := base.Parse(rssFeed.Channel.Link); err == nil && rssFeed.Channel.Link != "" {
+		site = link
+	}
+	pageIcons, err := models.DiscoverIconURLs(site.String())
+	if err != nil {
+		log.Printf("Error discovering icons of %v: %v", site, err)
+	}
+	for _, icon := range pageIcons {
+		add(site, icon)
+	}
+	add(site, "/favicon.ico")
+	add(base, "/favicon.ico")
+	return candidates
+}
+
+func fetchIcon(iconUrl string) (string, []byte, error) {
+	client := &http.Client{
+		Timeout: iconRequestTimeout,
+	}
+	resp, err := client.Get(iconUrl)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch URL: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("URL returned status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read icon: %v", err)
+	}
+	if len(data) > maxIconSize {
+		return "", nil, fmt.Errorf("icon is larger than %d bytes", maxIconSize)
+	}
+	if len(data) == 0 {
+		return "", nil, errors.New("icon is empty")
+	}
+	contentType := http.DetectContentType(data)
+	if !allowedIconTypes[contentType] {
+		return "", nil, fmt.Errorf("unsupported icon content-type: %s", contentType)
+	}
+	return contentType, data, nil
+}
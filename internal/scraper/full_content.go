@@ -0,0 +1,81 @@
+package scraper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/extractor"
+)
+
+const (
+	maxArticleSize        = 2 << 20
+	maxContentLength      = 100 << 10
+	articleRequestTimeout = 10 * time.Second
+)
+
+// fetchFullContent downloads the article of every post, extracts its main
+// content and stores it, also on the given posts, never running more than
+// FullContentConcurrency downloads at once across all feeds.
+func (s *RSSScraper) fetchFullContent(feed *database.Feed, posts []database.Post) {
+	slots := s.contentSlots()
+	wg := sync.WaitGroup{}
+	for i := range posts {
+		wg.Add(1)
+		go func(post *database.Post) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			content, err := fetchArticleContent(post.Url)
+			if err != nil {
+				log.Printf("Error fetching full content of post %v (%v) from feed %v: %v", post.Title, post.Url, feed.ID, err)
+				return
+			}
+			post.Content = sql.NullString{String: content, Valid: true}
+			err = s.Database.UpdatePostContent(context.Background(), database.UpdatePostContentParams{
+				ID:      post.ID,
+				Content: post.Content,
+			})
+			if err != nil {
+				log.Printf("Error saving full content of post %v (%v): %v", post.Title, post.ID, err)
+			}
+		}(&posts[i])
+	}
+	wg.Wait()
+}
+
+// contentSlots returns the semaphore shared by the full content downloads,
+// created on first use so that it does not depend on Start being called.
+func (s *RSSScraper) contentSlots() chan struct{} {
+	s.fullContentSlotsOnce.Do(func() {
+		s.fullContentSlots = make(chan struct{}, max(s.FullContentConcurrency, 1))
+	})
+	return s.fullContentSlots
+}
+
+func fetchArticleContent(articleUrl string) (string, error) {
+	client := &http.Client{
+		Timeout: articleRequestTimeout,
+	}
+	resp, err := client.Get(articleUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch URL: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("URL returned status code: %d", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.Contains(strings.ToLower(contentType), "html") {
+		return "", fmt.Errorf("URL does not appear to be an HTML page (content-type: %s)", contentType)
+	}
+	// Links are resolved against the final URL, after any redirect.
+	return extractor.Extract(io.LimitReader(resp.Body, maxArticleSize), resp.Request.URL, maxContentLength)
+}
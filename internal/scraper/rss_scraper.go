@@ -3,20 +3,32 @@ package scraper
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/mellomaths/rss-aggregator/internal/database"
+	"github.com/mellomaths/rss-aggregator/internal/filters"
 	"github.com/mellomaths/rss-aggregator/internal/models"
+	"github.com/mellomaths/rss-aggregator/internal/urls"
 )
 
 type RSSScraper struct {
-	Database            *database.Queries
-	Concurrency         int
-	TimeBetweenRequests time.Duration
+	Database               *database.Queries
+	Concurrency            int
+	TimeBetweenRequests    time.Duration
+	FullContentConcurrency int
+	MaxResponseSize        int64
+	MaxItemsPerFetch       int
+	MaxTitleLength         int
+	MaxDescriptionLength   int
+	fullContentSlots       chan struct{}
+	fullContentSlotsOnce   sync.Once
 }
 
 func (s *RSSScraper) Start() {
@@ -41,32 +53,69 @@ func (s *RSSScraper) Start() {
 func (s *RSSScraper) scrapeFeed(wg *sync.WaitGroup, feed *database.Feed) {
 	defer wg.Done()
 	log.Printf("Scraping feed %v (%v)", feed.Name, feed.ID)
-	rssFeed, err := models.GetRSSFeedFromURL(feed.Url)
-	if err != nil {
-		log.Printf("Error getting RSS feed from URL %v: %v", feed.Url, err)
+	warnings := []string{}
+	rssFeed, fetchErr := models.GetRSSFeedFromURLWithLimit(feed.Url, s.MaxResponseSize)
+	if fetchErr != nil {
+		log.Printf("Error getting RSS feed from URL %v: %v", feed.Url, fetchErr)
+		if errors.Is(fetchErr, models.ErrFeedTooLarge) {
+			warnings = append(warnings, fetchErr.Error())
+		}
 	}
+	s.recordError(feed, fetchErr)
 	log.Printf("Processing RSS feed %v (%v)", rssFeed.Channel.Title, feed.ID)
+	rssFeed.ResolveURLs(feed.Url)
 	items := rssFeed.Channel.Items
+	if len(items) > s.MaxItemsPerFetch {
+		warnings = append(warnings, fmt.Sprintf("feed has %d items, only the first %d were processed", len(items), s.MaxItemsPerFetch))
+		items = items[:s.MaxItemsPerFetch]
+	}
+	truncatedPosts := 0
+	newPosts := []database.Post{}
 	for _, item := range items {
+		title, titleTruncated := truncate(item.Title, s.MaxTitleLength)
+		itemDescription, descriptionTruncated := truncate(item.Description, s.MaxDescriptionLength)
 		description := sql.NullString{}
-		if item.Description != "" {
-			description.String = item.Description
+		if itemDescription != "" {
+			description.String = itemDescription
 			description.Valid = true
 		}
+		enclosureUrl := sql.NullString{}
+		enclosureType := sql.NullString{}
+		if item.Enclosure.Url != "" {
+			enclosureUrl.String = item.Enclosure.Url
+			enclosureUrl.Valid = true
+			enclosureType.String = item.Enclosure.Type
+			enclosureType.Valid = item.Enclosure.Type != ""
+		}
+		author := sql.NullString{}
+		if authorName, _ := truncate(item.AuthorName(), s.MaxTitleLength); authorName != "" {
+			author.String = authorName
+			author.Valid = true
+		}
 		publishedAt, err := time.Parse(time.RFC1123Z, item.PubDate)
 		if err != nil {
 			log.Printf("Error parsing published date %v: %v", item.PubDate, err)
 			continue
 		}
-		_, err = s.Database.CreatePost(context.Background(), database.CreatePostParams{
-			ID:          uuid.New(),
-			CreatedAt:   time.Now().UTC(),
-			UpdatedAt:   time.Now().UTC(),
-			Title:       item.Title,
-			Url:         item.Link,
-			Description: description,
-			PublishedAt: publishedAt,
-			FeedID:      feed.ID,
+		canonicalUrl, err := urls.Canonicalize(item.Link)
+		if err != nil {
+			log.Printf("Error canonicalizing post URL %v: %v", item.Link, err)
+			continue
+		}
+		post, err := s.Database.CreatePost(context.Background(), database.CreatePostParams{
+			ID:            uuid.New(),
+			CreatedAt:     time.Now().UTC(),
+			UpdatedAt:     time.Now().UTC(),
+			Title:         title,
+			Url:           item.Link,
+			Description:   description,
+			PublishedAt:   publishedAt,
+			FeedID:        feed.ID,
+			EnclosureUrl:  enclosureUrl,
+			EnclosureType: enclosureType,
+			CanonicalUrl:  canonicalUrl,
+			Truncated:     titleTruncated || descriptionTruncated,
+			Author:        author,
 		})
 		if err != nil {
 			if strings.Contains(err.Error(), "duplicate key") {
@@ -75,10 +124,116 @@ func (s *RSSScraper) scrapeFeed(wg *sync.WaitGroup, feed *database.Feed) {
 			log.Printf("Error creating post %v (%v): %v", item.Title, item.Link, err)
 			continue
 		}
+		newPosts = append(newPosts, post)
+		if post.Truncated {
+			truncatedPosts++
+		}
+	}
+	if truncatedPosts > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d posts had their title or description truncated", truncatedPosts))
+	}
+	s.recordWarnings(feed, warnings)
+	// A failed fetch leaves an empty channel, which must not erase the metadata.
+	if fetchErr == nil {
+		s.updateMetadata(feed, rssFeed)
 	}
+	if feed.FetchFullContent {
+		s.fetchFullContent(feed, newPosts)
+	}
+	s.applyFilterRules(feed, newPosts)
 	log.Printf("Feed %v (%v) processed successfully, %v posts found", feed.Name, feed.ID, len(items))
-	_, err = s.Database.MarkFeedAsFetched(context.Background(), feed.ID)
+	s.refreshFeedIcon(feed, rssFeed)
+	_, err := s.Database.MarkFeedAsFetched(context.Background(), feed.ID)
 	if err != nil {
 		log.Printf("Error marking feed as fetched %v (%v): %v", feed.Name, feed.ID, err)
 	}
 }
+
+// recordWarnings stores the warnings of the last fetch on the feed, clearing
+// the previous ones when the fetch had none.
+func (s *RSSScraper) recordWarnings(feed *database.Feed, warnings []string) {
+	lastWarning := sql.NullString{}
+	if len(warnings) > 0 {
+		lastWarning.String = strings.Join(warnings, "; ")
+		lastWarning.Valid = true
+		log.Printf("Feed %v (%v) fetched with warnings: %v", feed.Name, feed.ID, lastWarning.String)
+	}
+	err := s.Database.SetFeedWarning(context.Background(), database.SetFeedWarningParams{
+		ID:          feed.ID,
+		LastWarning: lastWarning,
+	})
+	if err != nil {
+		log.Printf("Error recording warnings of feed %v (%v): %v", feed.Name, feed.ID, err)
+	}
+}
+
+// recordError stores the error of the last fetch on the feed, clearing the
+// previous one when the fetch succeeded.
+func (s *RSSScraper) recordError(feed *database.Feed, fetchErr error) {
+	lastError := sql.NullString{}
+	if fetchErr != nil {
+		lastError.String = fetchErr.Error()
+		lastError.Valid = true
+	}
+	err := s.Database.SetFeedError(context.Background(), database.SetFeedErrorParams{
+		ID:        feed.ID,
+		LastError: lastError,
+	})
+	if err != nil {
+		log.Printf("Error recording error of feed %v (%v): %v", feed.Name, feed.ID, err)
+	}
+}
+
+// applyFilterRules runs the filter rules of the users following the feed on
+// its new posts, after their full content was fetched.
+func (s *RSSScraper) applyFilterRules(feed *database.Feed, posts []database.Post) {
+	if len(posts) == 0 {
+		return
+	}
+	dbRules, err := s.Database.GetFilterRulesForFeed(context.Background(), feed.ID)
+	if err != nil {
+		log.Printf("Error getting filter rules of feed %v (%v): %v", feed.Name, feed.ID, err)
+		return
+	}
+	for _, dbRule := range dbRules {
+		rule, err := filters.Compile(dbRule)
+		if err != nil {
+			log.Printf("Error compiling filter rule %v: %v", dbRule.ID, err)
+			continue
+		}
+		for _, post := range posts {
+			if !rule.Matches(post) {
+				continue
+			}
+			if err := rule.Apply(context.Background(), s.Database, post); err != nil {
+				log.Printf("Error applying filter rule %v to post %v: %v", rule.ID, post.ID, err)
+			}
+		}
+	}
+}
+
+// updateMetadata stores the description and language advertised by the
+// channel, used to search the feed directory.
+func (s *RSSScraper) updateMetadata(feed *database.Feed, rssFeed models.RSSFeed) {
+	description, _ := truncate(strings.TrimSpace(rssFeed.Channel.Description), s.MaxDescriptionLength)
+	language := strings.TrimSpace(rssFeed.Channel.Language)
+	err := s.Database.UpdateFeedMetadata(context.Background(), database.UpdateFeedMetadataParams{
+		ID:          feed.ID,
+		Description: sql.NullString{String: description, Valid: description != ""},
+		Language:    sql.NullString{String: language, Valid: language != ""},
+	})
+	if err != nil {
+		log.Printf("Error updating metadata of feed %v (%v): %v", feed.Name, feed.ID, err)
+	}
+}
+
+// truncate shortens s to at most maxLength bytes without splitting a rune.
+func truncate(s string, maxLength int) (string, bool) {
+	if len(s) <= maxLength {
+		return s, false
+	}
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength], true
+}
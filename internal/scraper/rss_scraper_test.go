@@ -0,0 +1,35 @@
+package scraper
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		maxLength     int
+		want          string
+		wantTruncated bool
+	}{
+		{"short", "Café", 10, "Café", false},
+		{"exact length", "Café", 5, "Café", false},
+		{"ascii cut", "Hello, world", 5, "Hello", true},
+		{"cut inside a rune steps back", "Café", 4, "Caf", true},
+		{"cut after a rune", "Caféine", 5, "Café", true},
+		{"cut inside a four byte rune", "a😀b", 3, "a", true},
+		{"no room for the first rune", "😀", 2, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncate(tt.s, tt.maxLength)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("truncate(%q, %d) = %q, %v, want %q, %v", tt.s, tt.maxLength, got, truncated, tt.want, tt.wantTruncated)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q splits a rune", tt.s, tt.maxLength, got)
+			}
+		})
+	}
+}
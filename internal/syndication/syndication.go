@@ -0,0 +1,292 @@
+// Package syndication writes lists of posts as RSS 2.0, Atom and JSON Feed
+// documents, to be read by other feed readers.
+package syndication
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+const (
+	FormatRSS  = "xml"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+var Formats = []string{FormatRSS, FormatAtom, FormatJSON}
+
+// ContentTypes maps each format to the content type it is served with.
+var ContentTypes = map[string]string{
+	FormatRSS:  "application/rss+xml; charset=utf-8",
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatJSON: "application/feed+json; charset=utf-8",
+}
+
+type Feed struct {
+	// ID identifies the feed for as long as it exists, such as a urn:uuid.
+	ID          string
+	Title       string
+	Description string
+	// SelfUrl is the URL the document is served at, without the secrets of
+	// its query, which must not be published in the document.
+	SelfUrl string
+	// SiteUrl is the web page the feed is about.
+	SiteUrl string
+	Author  string
+	Updated time.Time
+	Items   []Item
+}
+
+type Item struct {
+	ID            string
+	Title         string
+	Url           string
+	Summary       string
+	Content       string
+	Author        string
+	PublishedAt   time.Time
+	EnclosureUrl  string
+	EnclosureType string
+}
+
+// Write encodes the feed in the given format.
+func Write(w io.Writer, format string, feed Feed) error {
+	switch format {
+	case FormatAtom:
+		return writeXML(w, newAtomFeed(feed))
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newJSONFeed(feed))
+	default:
+		return writeXML(w, newRSSFeed(feed))
+	}
+}
+
+func writeXML(w io.Writer, document interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Guid        rssGuid       `xml:"guid"`
+	Description string        `xml:"description,omitempty"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGuid struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+func newRSSFeed(feed Feed) rssFeed {
+	items := make([]rssItem, len(feed.Items))
+	for i, item := range feed.Items {
+		items[i] = rssItem{
+			Title:       item.Title,
+			Link:        item.Url,
+			Guid:        rssGuid{IsPermaLink: "false", Value: item.ID},
+			Description: firstNonEmpty(item.Content, item.Summary),
+			Creator:     item.Author,
+			PubDate:     item.PublishedAt.UTC().Format(time.RFC1123Z),
+		}
+		if item.EnclosureUrl != "" {
+			items[i].Enclosure = &rssEnclosure{Url: item.EnclosureUrl, Type: item.EnclosureType}
+		}
+	}
+	return rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.SiteUrl,
+			Description:   feed.Description,
+			AtomLink:      atomLink{Rel: "self", Type: "application/rss+xml", Href: feed.SelfUrl},
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			Items:         items,
+		},
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Links     []atomLink  `xml:"link"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Author    *atomAuthor `xml:"author"`
+	Summary   *atomText   `xml:"summary"`
+	Content   *atomText   `xml:"content"`
+}
+
+func newAtomFeed(feed Feed) atomFeed {
+	entries := make([]atomEntry, len(feed.Items))
+	for i, item := range feed.Items {
+		published := item.PublishedAt.UTC().Format(time.RFC3339)
+		entries[i] = atomEntry{
+			Title:     item.Title,
+			ID:        "urn:uuid:" + item.ID,
+			Links:     []atomLink{{Rel: "alternate", Href: item.Url}},
+			Updated:   published,
+			Published: published,
+		}
+		if item.EnclosureUrl != "" {
+			entries[i].Links = append(entries[i].Links, atomLink{Rel: "enclosure", Type: item.EnclosureType, Href: item.EnclosureUrl})
+		}
+		if item.Author != "" {
+			entries[i].Author = &atomAuthor{Name: item.Author}
+		}
+		if item.Summary != "" {
+			entries[i].Summary = &atomText{Type: "html", Value: item.Summary}
+		}
+		if item.Content != "" {
+			entries[i].Content = &atomText{Type: "html", Value: item.Content}
+		}
+	}
+	af := atomFeed{
+		Title:   feed.Title,
+		ID:      feed.ID,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Rel: "self", Type: "application/atom+xml", Href: feed.SelfUrl}},
+		Author:  atomAuthor{Name: feed.Author},
+		Entries: entries,
+	}
+	if feed.SiteUrl != "" {
+		af.Links = append(af.Links, atomLink{Rel: "alternate", Type: "text/html", Href: feed.SiteUrl})
+	}
+	return af
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	HomePageUrl string         `json:"home_page_url,omitempty"`
+	FeedUrl     string         `json:"feed_url"`
+	Authors     []jsonAuthor   `json:"authors,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	Url           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHtml   string           `json:"content_html"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	Authors       []jsonAuthor     `json:"authors,omitempty"`
+	Attachments   []jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAttachment struct {
+	Url      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+func newJSONFeed(feed Feed) jsonFeed {
+	items := make([]jsonFeedItem, len(feed.Items))
+	for i, item := range feed.Items {
+		items[i] = jsonFeedItem{
+			ID:            item.ID,
+			Url:           item.Url,
+			Title:         item.Title,
+			ContentHtml:   firstNonEmpty(item.Content, item.Summary),
+			Summary:       item.Summary,
+			DatePublished: item.PublishedAt.UTC().Format(time.RFC3339),
+		}
+		if item.Author != "" {
+			items[i].Authors = []jsonAuthor{{Name: item.Author}}
+		}
+		if item.EnclosureUrl != "" {
+			items[i].Attachments = []jsonAttachment{{Url: item.EnclosureUrl, MimeType: item.EnclosureType}}
+		}
+	}
+	jf := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		Description: feed.Description,
+		HomePageUrl: feed.SiteUrl,
+		FeedUrl:     feed.SelfUrl,
+		Items:       items,
+	}
+	if feed.Author != "" {
+		jf.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+	return jf
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,174 @@
+package syndication
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))
+	return Feed{
+		ID:          "urn:uuid:6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f",
+		Title:       "Timeline",
+		Description: "Timeline",
+		SelfUrl:     "https://reader.example.com/v1/users/u/feeds/timeline.xml",
+		SiteUrl:     "https://reader.example.com/",
+		Author:      "Ada",
+		Updated:     published,
+		Items: []Item{
+			{
+				ID:            "0b9c8d7e-6f5a-4b3c-2d1e-0f9a8b7c6d5e",
+				Title:         "Fish & chips",
+				Url:           "https://example.com/posts/1",
+				Summary:       "<p>Summary</p>",
+				Content:       "<p>Content</p>",
+				Author:        "Grace",
+				PublishedAt:   published,
+				EnclosureUrl:  "https://example.com/episode.mp3",
+				EnclosureType: "audio/mpeg",
+			},
+			{
+				ID:          "1c0d9e8f-7a6b-4c5d-3e2f-1a0b9c8d7e6f",
+				Title:       "Summary only",
+				Url:         "https://example.com/posts/2",
+				Summary:     "Plain summary",
+				PublishedAt: published.Add(-time.Hour),
+			},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatRSS, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Timeline</title>
+    <link>https://reader.example.com/</link>
+    <description>Timeline</description>
+    <atom:link rel="self" type="application/rss+xml" href="https://reader.example.com/v1/users/u/feeds/timeline.xml"></atom:link>
+    <lastBuildDate>Wed, 01 May 2024 10:00:00 +0000</lastBuildDate>
+    <item>
+      <title>Fish &amp; chips</title>
+      <link>https://example.com/posts/1</link>
+      <guid isPermaLink="false">0b9c8d7e-6f5a-4b3c-2d1e-0f9a8b7c6d5e</guid>
+      <description>&lt;p&gt;Content&lt;/p&gt;</description>
+      <dc:creator>Grace</dc:creator>
+      <pubDate>Wed, 01 May 2024 10:00:00 +0000</pubDate>
+      <enclosure url="https://example.com/episode.mp3" type="audio/mpeg" length="0"></enclosure>
+    </item>
+    <item>
+      <title>Summary only</title>
+      <link>https://example.com/posts/2</link>
+      <guid isPermaLink="false">1c0d9e8f-7a6b-4c5d-3e2f-1a0b9c8d7e6f</guid>
+      <description>Plain summary</description>
+      <pubDate>Wed, 01 May 2024 09:00:00 +0000</pubDate>
+    </item>
+  </channel>
+</rss>
+`},
+		{FormatAtom, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Timeline</title>
+  <id>urn:uuid:6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f</id>
+  <updated>2024-05-01T10:00:00Z</updated>
+  <link rel="self" type="application/atom+xml" href="https://reader.example.com/v1/users/u/feeds/timeline.xml"></link>
+  <link rel="alternate" type="text/html" href="https://reader.example.com/"></link>
+  <author>
+    <name>Ada</name>
+  </author>
+  <entry>
+    <title>Fish &amp; chips</title>
+    <id>urn:uuid:0b9c8d7e-6f5a-4b3c-2d1e-0f9a8b7c6d5e</id>
+    <link rel="alternate" href="https://example.com/posts/1"></link>
+    <link rel="enclosure" type="audio/mpeg" href="https://example.com/episode.mp3"></link>
+    <updated>2024-05-01T10:00:00Z</updated>
+    <published>2024-05-01T10:00:00Z</published>
+    <author>
+      <name>Grace</name>
+    </author>
+    <summary type="html">&lt;p&gt;Summary&lt;/p&gt;</summary>
+    <content type="html">&lt;p&gt;Content&lt;/p&gt;</content>
+  </entry>
+  <entry>
+    <title>Summary only</title>
+    <id>urn:uuid:1c0d9e8f-7a6b-4c5d-3e2f-1a0b9c8d7e6f</id>
+    <link rel="alternate" href="https://example.com/posts/2"></link>
+    <updated>2024-05-01T09:00:00Z</updated>
+    <published>2024-05-01T09:00:00Z</published>
+    <summary type="html">Plain summary</summary>
+  </entry>
+</feed>
+`},
+		{FormatJSON, `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Timeline",
+  "description": "Timeline",
+  "home_page_url": "https://reader.example.com/",
+  "feed_url": "https://reader.example.com/v1/users/u/feeds/timeline.xml",
+  "authors": [
+    {
+      "name": "Ada"
+    }
+  ],
+  "items": [
+    {
+      "id": "0b9c8d7e-6f5a-4b3c-2d1e-0f9a8b7c6d5e",
+      "url": "https://example.com/posts/1",
+      "title": "Fish \u0026 chips",
+      "content_html": "\u003cp\u003eContent\u003c/p\u003e",
+      "summary": "\u003cp\u003eSummary\u003c/p\u003e",
+      "date_published": "2024-05-01T10:00:00Z",
+      "authors": [
+        {
+          "name": "Grace"
+        }
+      ],
+      "attachments": [
+        {
+          "url": "https://example.com/episode.mp3",
+          "mime_type": "audio/mpeg"
+        }
+      ]
+    },
+    {
+      "id": "1c0d9e8f-7a6b-4c5d-3e2f-1a0b9c8d7e6f",
+      "url": "https://example.com/posts/2",
+      "title": "Summary only",
+      "content_html": "Plain summary",
+      "summary": "Plain summary",
+      "date_published": "2024-05-01T09:00:00Z"
+    }
+  ]
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, tt.format, testFeed()); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteWithoutSiteUrl(t *testing.T) {
+	feed := testFeed()
+	feed.SiteUrl = ""
+	feed.Items = nil
+	var b strings.Builder
+	if err := Write(&b, FormatAtom, feed); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if strings.Contains(b.String(), `rel="alternate"`) {
+		t.Errorf("Write() has an alternate link without a site url:\n%s", b.String())
+	}
+}
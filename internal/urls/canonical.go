@@ -0,0 +1,73 @@
+package urls
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams lists the query parameters that only carry tracking data.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "mc_cid": true,
+	"mc_eid": true, "igshid": true, "yclid": true, "_hsenc": true, "_hsmi": true,
+	"ref_src": true, "ref_url": true,
+}
+
+// Canonicalize returns the canonical form of an http or https URL, used to
+// detect the same resource behind different spellings of its URL:
+//   - the scheme is https and the host is lowercased, without default port or trailing dot
+//   - dot segments, duplicated slashes and the trailing slash are removed from the path
+//   - utm_* and other tracking parameters are removed and the query is sorted
+//   - the fragment is removed
+func Canonicalize(rawUrl string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", errors.New("url must start with https:// or http://")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "", errors.New("url must have a host")
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	isDefaultPort := (scheme == "http" && port == "80") || (scheme == "https" && port == "443")
+	if port != "" && !isDefaultPort {
+		host = host + ":" + port
+	}
+	canonical := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     canonicalPath(u.Path),
+		RawQuery: canonicalQuery(u.Query()),
+	}
+	return canonical.String(), nil
+}
+
+func canonicalPath(p string) string {
+	if p == "" || p == "/" {
+		return ""
+	}
+	cleaned := path.Clean("/" + p)
+	if cleaned == "/" {
+		return ""
+	}
+	return cleaned
+}
+
+func canonicalQuery(query url.Values) string {
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	// Encode sorts the parameters by key.
+	return query.Encode()
+}
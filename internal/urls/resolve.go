@@ -0,0 +1,67 @@
+// Package urls resolves and normalizes the URLs found in feeds.
+package urls
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// htmlUrlAttribute matches href and src attributes, quoted or not.
+var htmlUrlAttribute = regexp.MustCompile(`(?i)(\s(?:href|src)\s*=\s*)(?:"([^"]*)"|'([^']*)'|([^\s>"']+))`)
+
+// Base resolves each reference against the previous one, starting from the
+// first, and returns the resulting base URL. Empty or invalid references are
+// skipped.
+func Base(refs ...string) *url.URL {
+	var base *url.URL
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			continue
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		base = u
+	}
+	return base
+}
+
+// Resolve returns ref resolved against base. Absolute references, fragments
+// and references that cannot be parsed are returned as they are.
+func Resolve(base *url.URL, ref string) string {
+	trimmed := strings.TrimSpace(ref)
+	if base == nil || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return ref
+	}
+	u, err := url.Parse(trimmed)
+	if err != nil || u.IsAbs() {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// ResolveHTML resolves the href and src attributes of an HTML fragment
+// against base.
+func ResolveHTML(base *url.URL, fragment string) string {
+	if base == nil {
+		return fragment
+	}
+	return htmlUrlAttribute.ReplaceAllStringFunc(fragment, func(attr string) string {
+		match := htmlUrlAttribute.FindStringSubmatch(attr)
+		switch {
+		case match[2] != "":
+			return match[1] + `"` + Resolve(base, match[2]) + `"`
+		case match[3] != "":
+			return match[1] + `'` + Resolve(base, match[3]) + `'`
+		case match[4] != "":
+			return match[1] + `"` + Resolve(base, match[4]) + `"`
+		}
+		return attr
+	})
+}
@@ -0,0 +1,74 @@
+package urls
+
+import "testing"
+
+func TestBase(t *testing.T) {
+	tests := []struct {
+		name string
+		refs []string
+		want string
+	}{
+		{"no reference", nil, ""},
+		{"single reference", []string{"https://example.com/feed.xml"}, "https://example.com/feed.xml"},
+		{"relative channel link", []string{"https://example.com/blog/feed.xml", "/blog/"}, "https://example.com/blog/"},
+		{"absolute channel link", []string{"https://feeds.example.com/x", "https://example.com/"}, "https://example.com/"},
+		{"empty references are skipped", []string{"https://example.com/a/", " ", ""}, "https://example.com/a/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if base := Base(tt.refs...); base != nil {
+				got = base.String()
+			}
+			if got != tt.want {
+				t.Errorf("Base(%q) = %q, want %q", tt.refs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	base := Base("https://example.com/blog/feed.xml")
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"post.html", "https://example.com/blog/post.html"},
+		{"/about", "https://example.com/about"},
+		{"../about", "https://example.com/about"},
+		{"//cdn.example.com/a.mp3", "https://cdn.example.com/a.mp3"},
+		{"?page=2", "https://example.com/blog/feed.xml?page=2"},
+		{"https://other.example.com/x", "https://other.example.com/x"},
+		{"mailto:me@example.com", "mailto:me@example.com"},
+		{"#top", "#top"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Resolve(base, tt.ref); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+	if got := Resolve(nil, "post.html"); got != "post.html" {
+		t.Errorf("Resolve(nil, %q) = %q, want it unchanged", "post.html", got)
+	}
+}
+
+func TestResolveHTML(t *testing.T) {
+	base := Base("https://example.com/blog/")
+	tests := []struct {
+		fragment string
+		want     string
+	}{
+		{`<a href="post.html">x</a>`, `<a href="https://example.com/blog/post.html">x</a>`},
+		{`<img src='/a.png'>`, `<img src='https://example.com/a.png'>`},
+		{`<img src=a.png alt=x>`, `<img src="https://example.com/blog/a.png" alt=x>`},
+		{`<a HREF="https://other.example.com/">x</a>`, `<a HREF="https://other.example.com/">x</a>`},
+		{`<a href="#note">x</a>`, `<a href="#note">x</a>`},
+		{`<p data-src="a.png">x</p>`, `<p data-src="a.png">x</p>`},
+	}
+	for _, tt := range tests {
+		if got := ResolveHTML(base, tt.fragment); got != tt.want {
+			t.Errorf("ResolveHTML(%q) = %q, want %q", tt.fragment, got, tt.want)
+		}
+	}
+}
@@ -22,11 +22,17 @@ func main() {
 		log.Fatal("Error connecting to database: " + err.Error())
 	}
 	defer conn.Close()
-	apiCfg := api.NewApiConfig(conn)
+	apiCfg := api.NewApiConfig(conn, settings.BaseUrl)
+	apiCfg.StartJobs()
 	rssScraper := scraper.RSSScraper{
-		Database:            apiCfg.DATABASE,
-		Concurrency:         10,
-		TimeBetweenRequests: 10 * time.Minute,
+		Database:               apiCfg.DATABASE,
+		Concurrency:            10,
+		TimeBetweenRequests:    10 * time.Minute,
+		FullContentConcurrency: 4,
+		MaxResponseSize:        int64(settings.ScraperMaxResponseSize),
+		MaxItemsPerFetch:       settings.ScraperMaxItemsPerFetch,
+		MaxTitleLength:         settings.ScraperMaxTitleLength,
+		MaxDescriptionLength:   settings.ScraperMaxDescriptionLength,
 	}
 	go rssScraper.Start()
 	router := apiCfg.SetupRouter()
@@ -0,0 +1,25 @@
+-- name: CreateCategory :one
+INSERT INTO categories (id, created_at, updated_at, user_id, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpsertCategory :one
+INSERT INTO categories (id, created_at, updated_at, user_id, name)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetCategoriesForUser :many
+SELECT * FROM categories WHERE user_id = $1 ORDER BY name;
+
+-- name: GetCategory :one
+SELECT * FROM categories WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCategory :exec
+DELETE FROM categories WHERE id = $1 AND user_id = $2;
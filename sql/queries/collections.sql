@@ -0,0 +1,39 @@
+-- name: CreateCollection :one
+INSERT INTO collections (id, created_at, updated_at, user_id, title, description)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetCollectionsForUser :many
+SELECT * FROM collections WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: GetCollection :one
+SELECT * FROM collections WHERE id = $1;
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET title = $3, description = $4, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections WHERE id = $1 AND user_id = $2;
+
+-- name: AddPostToCollection :exec
+INSERT INTO collection_posts (collection_id, post_id, added_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (collection_id, post_id) DO NOTHING;
+
+-- name: RemovePostFromCollection :exec
+DELETE FROM collection_posts WHERE collection_id = $1 AND post_id = $2;
+
+-- name: GetCollectionPosts :many
+SELECT p.*
+FROM posts p
+JOIN collection_posts cp ON cp.post_id = p.id
+WHERE cp.collection_id = $1
+ORDER BY cp.added_at DESC, p.id
+LIMIT $2
+OFFSET $3;
+
+-- name: CountCollectionPosts :one
+SELECT COUNT(*) FROM collection_posts WHERE collection_id = $1;
@@ -3,8 +3,59 @@ INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: UpsertFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, feed_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING *;
+
 -- name: GetFeedsFollowedByUser :many
-SELECT * FROM feed_follows WHERE user_id = $1;
+SELECT * FROM feed_follows
+WHERE user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(category_id)::uuid IS NULL OR category_id = sqlc.narg(category_id)::uuid)
+ORDER BY priority DESC, created_at DESC, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountFeedsFollowedByUser :one
+SELECT COUNT(*) FROM feed_follows
+WHERE user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(category_id)::uuid IS NULL OR category_id = sqlc.narg(category_id)::uuid);
+
+-- name: GetFeedFollow :one
+SELECT * FROM feed_follows WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateFeedFollow :one
+UPDATE feed_follows
+SET custom_title = $3, notes = $4, priority = $5, hide_from_timeline = $6, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
 
 -- name: DeleteFeedFollow :exec
 DELETE FROM feed_follows WHERE id = $1 AND user_id = $2;
+
+-- name: SetFeedFollowCategory :one
+UPDATE feed_follows
+SET category_id = sqlc.narg(category_id), updated_at = NOW()
+WHERE id = sqlc.arg(id)
+    AND user_id = sqlc.arg(user_id)
+    AND (
+        sqlc.narg(category_id)::uuid IS NULL
+        OR EXISTS (
+            SELECT 1 FROM categories c
+            WHERE c.id = sqlc.narg(category_id)::uuid AND c.user_id = sqlc.arg(user_id)
+        )
+    )
+RETURNING *;
+
+-- name: GetFeedFollowsForExport :many
+SELECT f.name, f.url, ff.custom_title, c.name AS category_name
+FROM feed_follows ff
+JOIN feeds f ON f.id = ff.feed_id
+LEFT JOIN categories c ON c.id = ff.category_id
+WHERE ff.user_id = $1
+ORDER BY c.name NULLS FIRST, ff.priority DESC, f.name;
+
+-- name: IsFollowingFeed :one
+SELECT EXISTS (
+    SELECT 1 FROM feed_follows WHERE user_id = $1 AND feed_id = $2
+);
@@ -0,0 +1,29 @@
+-- name: UpsertFeedIcon :one
+INSERT INTO feed_icons (feed_id, created_at, updated_at, source_url, content_type, data, etag)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    source_url = EXCLUDED.source_url,
+    content_type = EXCLUDED.content_type,
+    data = EXCLUDED.data,
+    etag = EXCLUDED.etag
+RETURNING *;
+
+-- name: GetFeedIcon :one
+SELECT * FROM feed_icons WHERE feed_id = $1;
+
+-- name: GetFeedIconUpdatedAt :one
+SELECT updated_at FROM feed_icons WHERE feed_id = $1;
+
+-- name: GetFeedIconFailure :one
+SELECT * FROM feed_icon_failures WHERE feed_id = $1;
+
+-- name: UpsertFeedIconFailure :exec
+INSERT INTO feed_icon_failures (feed_id, failures, retry_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (feed_id) DO UPDATE
+SET failures = EXCLUDED.failures,
+    retry_at = EXCLUDED.retry_at;
+
+-- name: DeleteFeedIconFailure :exec
+DELETE FROM feed_icon_failures WHERE feed_id = $1;
@@ -0,0 +1,13 @@
+-- name: CreateFeedToken :one
+INSERT INTO feed_tokens (id, created_at, user_id, kind, category_id, tag, token)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetFeedTokensForUser :many
+SELECT * FROM feed_tokens WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetFeedTokenByToken :one
+SELECT * FROM feed_tokens WHERE token = $1;
+
+-- name: DeleteFeedToken :exec
+DELETE FROM feed_tokens WHERE id = $1 AND user_id = $2;
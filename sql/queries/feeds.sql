@@ -1,13 +1,45 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, fetch_full_content, canonical_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: CreateFeedIfNotExists :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id, fetch_full_content, canonical_url)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (canonical_url) DO NOTHING
 RETURNING *;
 
 -- name: GetAllFeeds :many
-SELECT * 
-FROM feeds 
-ORDER BY created_at DESC 
-LIMIT $1 OFFSET $2;
+SELECT f.*, stats.follower_count, stats.last_post_at
+FROM feeds f
+CROSS JOIN LATERAL (
+    SELECT
+        (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = f.id) AS follower_count,
+        (SELECT MAX(p.published_at) FROM posts p WHERE p.feed_id = f.id) AS last_post_at
+) stats
+WHERE (
+        sqlc.narg(query)::text IS NULL
+        OR f.name ILIKE '%' || sqlc.narg(query)::text || '%'
+        OR f.url ILIKE '%' || sqlc.narg(query)::text || '%'
+        OR f.description ILIKE '%' || sqlc.narg(query)::text || '%'
+    )
+    AND (sqlc.narg(language)::text IS NULL OR lower(f.language) LIKE lower(sqlc.narg(language)::text) || '%')
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'followers' THEN stats.follower_count END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'active' THEN stats.last_post_at END DESC NULLS LAST,
+    f.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountFeeds :one
+SELECT COUNT(*)
+FROM feeds f
+WHERE (
+        sqlc.narg(query)::text IS NULL
+        OR f.name ILIKE '%' || sqlc.narg(query)::text || '%'
+        OR f.url ILIKE '%' || sqlc.narg(query)::text || '%'
+        OR f.description ILIKE '%' || sqlc.narg(query)::text || '%'
+    )
+    AND (sqlc.narg(language)::text IS NULL OR lower(f.language) LIKE lower(sqlc.narg(language)::text) || '%');
 
 -- name: GetNextFeedsToFetch :many
 SELECT * 
@@ -15,9 +47,72 @@ FROM feeds
 ORDER BY last_fetched_at ASC NULLS FIRST 
 LIMIT $1;
 
+-- name: SetFeedWarning :exec
+UPDATE feeds
+SET last_warning = $2
+WHERE id = $1;
+
+-- name: UpdateFeedMetadata :exec
+UPDATE feeds
+SET description = $2, language = $3
+WHERE id = $1;
+
 -- name: MarkFeedAsFetched :one
 UPDATE feeds 
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
+-- name: GetFeedByCanonicalUrl :one
+SELECT * FROM feeds WHERE canonical_url = $1;
+
+-- name: GetFeed :one
+SELECT * FROM feeds WHERE id = $1;
+
+-- name: UpdateFeed :one
+UPDATE feeds
+SET name = $2,
+    url = $3,
+    canonical_url = $4,
+    fetch_full_content = $5,
+    last_fetched_at = CASE WHEN url = $3 THEN last_fetched_at ELSE NULL END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1;
+
+-- name: CountOtherFeedUsers :one
+-- CountOtherFeedUsers counts the users other than the given one following the
+-- feed, and those who starred or collected its posts, lost with the feed.
+SELECT
+    (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = sqlc.arg(feed_id) AND ff.user_id <> sqlc.arg(user_id)) AS followers,
+    (
+        SELECT COUNT(DISTINCT saved.user_id)
+        FROM (
+            SELECT ps.user_id
+            FROM post_stars ps
+            JOIN posts p ON p.id = ps.post_id
+            WHERE p.feed_id = sqlc.arg(feed_id)
+            UNION ALL
+            SELECT c.user_id
+            FROM collection_posts cp
+            JOIN collections c ON c.id = cp.collection_id
+            JOIN posts p ON p.id = cp.post_id
+            WHERE p.feed_id = sqlc.arg(feed_id)
+        ) saved
+        WHERE saved.user_id <> sqlc.arg(user_id)
+    ) AS saving_users;
+
+-- name: SetFeedError :exec
+UPDATE feeds
+SET last_error = $2
+WHERE id = $1;
+
+-- name: GetFeedStats :one
+SELECT
+    (SELECT COUNT(*) FROM feed_follows ff WHERE ff.feed_id = sqlc.arg(feed_id)) AS follower_count,
+    (SELECT COUNT(*) FROM posts p WHERE p.feed_id = sqlc.arg(feed_id)) AS post_count,
+    (SELECT COUNT(*) FROM posts p WHERE p.feed_id = sqlc.arg(feed_id) AND p.published_at >= sqlc.arg(recent_since)::timestamptz) AS recent_post_count,
+    (SELECT MAX(p.published_at) FROM posts p WHERE p.feed_id = sqlc.arg(feed_id)) AS last_post_at;
@@ -0,0 +1,33 @@
+-- name: CreateFilterRuleRun :one
+INSERT INTO filter_rule_runs (id, created_at, updated_at, filter_rule_id, user_id, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetFilterRuleRun :one
+SELECT * FROM filter_rule_runs WHERE id = $1 AND filter_rule_id = $2 AND user_id = $3;
+
+-- name: CountActiveFilterRuleRunsForUser :one
+SELECT COUNT(*) FROM filter_rule_runs
+WHERE user_id = $1 AND status IN ('pending', 'running');
+
+-- name: ClaimFilterRuleRun :one
+UPDATE filter_rule_runs
+SET status = 'running', updated_at = NOW()
+WHERE id = (
+    SELECT id FROM filter_rule_runs
+    WHERE status = 'pending'
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RequeueFilterRuleRuns :exec
+UPDATE filter_rule_runs
+SET status = 'pending', updated_at = NOW()
+WHERE status = 'running';
+
+-- name: SetFilterRuleRunStatus :exec
+UPDATE filter_rule_runs
+SET status = $2, matched = $3, error = $4, updated_at = NOW()
+WHERE id = $1;
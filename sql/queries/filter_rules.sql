@@ -0,0 +1,39 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules (id, created_at, updated_at, user_id, feed_id, field, match_type, pattern, actions, tag)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetFilterRulesForUser :many
+SELECT * FROM filter_rules WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetFilterRule :one
+SELECT * FROM filter_rules WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateFilterRule :one
+UPDATE filter_rules
+SET feed_id = $3, field = $4, match_type = $5, pattern = $6, actions = $7, tag = $8, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteFilterRule :exec
+DELETE FROM filter_rules WHERE id = $1 AND user_id = $2;
+
+-- name: GetFilterRulesForFeed :many
+SELECT fr.*
+FROM filter_rules fr
+JOIN feed_follows ff ON ff.user_id = fr.user_id AND ff.feed_id = $1
+WHERE fr.feed_id IS NULL OR fr.feed_id = $1
+ORDER BY fr.created_at;
+
+-- name: GetPostsForFilterRule :many
+SELECT p.*
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR p.feed_id = sqlc.narg(feed_id)::uuid)
+ORDER BY p.published_at DESC, p.id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountFilterRulesForUser :one
+SELECT COUNT(*) FROM filter_rules WHERE user_id = $1;
@@ -0,0 +1,10 @@
+-- name: CreateMute :one
+INSERT INTO mutes (id, created_at, user_id, kind, value)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetMutesForUser :many
+SELECT * FROM mutes WHERE user_id = $1 ORDER BY kind, value;
+
+-- name: DeleteMute :exec
+DELETE FROM mutes WHERE id = $1 AND user_id = $2;
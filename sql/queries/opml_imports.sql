@@ -0,0 +1,50 @@
+-- name: CreateOpmlImport :one
+INSERT INTO opml_imports (id, created_at, updated_at, user_id, status, total)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetOpmlImport :one
+SELECT * FROM opml_imports WHERE id = $1 AND user_id = $2;
+
+-- name: SetOpmlImportStatus :exec
+UPDATE opml_imports
+SET status = $2, error = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: CreateOpmlImportResult :exec
+INSERT INTO opml_import_results (id, import_id, position, title, url, category, status, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetOpmlImportResults :many
+SELECT * FROM opml_import_results WHERE import_id = $1 ORDER BY position;
+
+-- name: GetPendingOpmlImportResults :many
+SELECT * FROM opml_import_results
+WHERE import_id = $1 AND status = 'pending'
+ORDER BY position;
+
+-- name: SetOpmlImportResultStatus :exec
+UPDATE opml_import_results
+SET status = $2, error = $3
+WHERE id = $1;
+
+-- name: CountActiveOpmlImportsForUser :one
+SELECT COUNT(*) FROM opml_imports
+WHERE user_id = $1 AND status IN ('pending', 'running');
+
+-- name: ClaimOpmlImport :one
+UPDATE opml_imports
+SET status = 'running', updated_at = NOW()
+WHERE id = (
+    SELECT id FROM opml_imports
+    WHERE status = 'pending'
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RequeueOpmlImports :exec
+UPDATE opml_imports
+SET status = 'pending', updated_at = NOW()
+WHERE status = 'running';
@@ -0,0 +1,7 @@
+-- name: HidePost :exec
+INSERT INTO post_hides (user_id, post_id, hidden_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnhidePost :exec
+DELETE FROM post_hides WHERE user_id = $1 AND post_id = $2;
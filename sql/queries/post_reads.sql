@@ -0,0 +1,31 @@
+-- name: MarkPostAsRead :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostAsUnread :exec
+DELETE FROM post_reads WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetUnreadCountsForUser :many
+SELECT ff.feed_id, COUNT(p.id) AS unread_count
+FROM feed_follows ff
+LEFT JOIN posts p ON p.feed_id = ff.feed_id
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads pr WHERE pr.post_id = p.id AND pr.user_id = ff.user_id
+    )
+    AND post_visible_to_user(p, ff.user_id)
+WHERE ff.user_id = $1
+    AND NOT ff.hide_from_timeline
+GROUP BY ff.feed_id
+ORDER BY ff.feed_id;
+
+-- name: MarkPostsAsRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT ff.user_id, p.id, sqlc.arg(read_at)::timestamptz
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR p.feed_id = sqlc.narg(feed_id)::uuid)
+    AND (sqlc.narg(category_id)::uuid IS NULL OR ff.category_id = sqlc.narg(category_id)::uuid)
+    AND (sqlc.narg(published_before)::timestamptz IS NULL OR p.published_at <= sqlc.narg(published_before)::timestamptz)
+ON CONFLICT (user_id, post_id) DO NOTHING;
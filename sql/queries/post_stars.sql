@@ -0,0 +1,19 @@
+-- name: StarPost :exec
+INSERT INTO post_stars (user_id, post_id, starred_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetStarredPostsForUser :many
+SELECT p.*
+FROM posts p
+JOIN post_stars ps ON ps.post_id = p.id
+WHERE ps.user_id = $1
+ORDER BY ps.starred_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CountStarredPostsForUser :one
+SELECT COUNT(*) FROM post_stars WHERE user_id = $1;
@@ -0,0 +1,22 @@
+-- name: SetPostTags :exec
+WITH removed AS (
+    DELETE FROM post_tags
+    WHERE user_id = sqlc.arg(user_id)
+        AND post_id = sqlc.arg(post_id)
+        AND NOT (tag = ANY(sqlc.arg(tags)::text[]))
+)
+INSERT INTO post_tags (user_id, post_id, tag, created_at)
+SELECT sqlc.arg(user_id)::uuid, sqlc.arg(post_id)::uuid, unnest(sqlc.arg(tags)::text[]), sqlc.arg(created_at)::timestamptz
+ON CONFLICT (user_id, post_id, tag) DO NOTHING;
+
+-- name: GetTagsForUser :many
+SELECT tag, COUNT(*) AS post_count
+FROM post_tags
+WHERE user_id = $1
+GROUP BY tag
+ORDER BY tag;
+
+-- name: AddPostTag :exec
+INSERT INTO post_tags (user_id, post_id, tag, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, post_id, tag) DO NOTHING;
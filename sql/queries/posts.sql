@@ -7,16 +7,142 @@ INSERT INTO posts (
     url,
     description,
     published_at,
-    feed_id
+    feed_id,
+    enclosure_url,
+    enclosure_type,
+    canonical_url,
+    truncated,
+    author
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 RETURNING *;
 
+-- name: UpdatePostContent :exec
+UPDATE posts
+SET content = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetPost :one
+SELECT * FROM posts WHERE id = $1;
+
+-- name: GetPostForUser :one
+SELECT p.*
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+WHERE p.id = $1 AND ff.user_id = $2;
+
 -- name: GetPostsForUser :many
 SELECT p.*
 FROM posts p
 JOIN feed_follows ff ON p.feed_id = ff.feed_id
-WHERE ff.user_id = $1
-ORDER BY p.published_at DESC
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (
+        sqlc.arg(status)::text = 'all'
+        OR (sqlc.arg(status)::text = 'read' AND pr.post_id IS NOT NULL)
+        OR (sqlc.arg(status)::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND (sqlc.narg(category_id)::uuid IS NULL OR ff.category_id = sqlc.narg(category_id)::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (
+        sqlc.narg(cursor_published_at)::timestamptz IS NULL
+        OR (p.published_at, p.id) < (sqlc.narg(cursor_published_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+    AND post_visible_to_user(p, ff.user_id)
+ORDER BY p.published_at DESC, p.id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: GetNewerPostsForUser :many
+SELECT p.*
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (
+        sqlc.arg(status)::text = 'all'
+        OR (sqlc.arg(status)::text = 'read' AND pr.post_id IS NOT NULL)
+        OR (sqlc.arg(status)::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND (sqlc.narg(category_id)::uuid IS NULL OR ff.category_id = sqlc.narg(category_id)::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND (p.published_at, p.id) > (sqlc.arg(cursor_published_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+    AND post_visible_to_user(p, ff.user_id)
+ORDER BY p.published_at ASC, p.id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: CountPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id
+LEFT JOIN post_reads pr ON pr.post_id = p.id AND pr.user_id = ff.user_id
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND (
+        sqlc.arg(status)::text = 'all'
+        OR (sqlc.arg(status)::text = 'read' AND pr.post_id IS NOT NULL)
+        OR (sqlc.arg(status)::text = 'unread' AND pr.post_id IS NULL)
+    )
+    AND (sqlc.narg(category_id)::uuid IS NULL OR ff.category_id = sqlc.narg(category_id)::uuid)
+    AND NOT ff.hide_from_timeline
+    AND (
+        sqlc.narg(tag)::text IS NULL
+        OR EXISTS (
+            SELECT 1 FROM post_tags pt
+            WHERE pt.post_id = p.id AND pt.user_id = ff.user_id AND pt.tag = sqlc.narg(tag)::text
+        )
+    )
+    AND post_visible_to_user(p, ff.user_id);
+
+-- name: SearchPostsForUser :many
+SELECT p.*,
+    ts_rank(posts_search_vector(title, description, content), query) AS rank,
+    ts_headline(
+        'english',
+        regexp_replace(concat_ws(' ', p.title, p.description, p.content), '<[^>]*>', ' ', 'g'),
+        query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'
+    ) AS snippet
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id,
+    to_tsquery('english', sqlc.arg(query)::text) query
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND NOT ff.hide_from_timeline
+    AND post_visible_to_user(p, ff.user_id)
+    AND posts_search_vector(title, description, content) @@ query
+ORDER BY rank DESC, p.published_at DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountSearchPostsForUser :one
+SELECT COUNT(*)
+FROM posts p
+JOIN feed_follows ff ON p.feed_id = ff.feed_id,
+    to_tsquery('english', sqlc.arg(query)::text) query
+WHERE ff.user_id = sqlc.arg(user_id)
+    AND NOT ff.hide_from_timeline
+    AND post_visible_to_user(p, ff.user_id)
+    AND posts_search_vector(title, description, content) @@ query;
+
+-- name: GetPostsForFeed :many
+SELECT * FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC, id DESC
 LIMIT $2
 OFFSET $3;
+
+-- name: CountPostsForFeed :one
+SELECT COUNT(*) FROM posts WHERE feed_id = $1;
@@ -5,3 +5,11 @@ RETURNING *;
 
 -- name: GetUserByApiKey :one
 SELECT * FROM users WHERE api_key = $1 LIMIT 1;
+
+-- name: GetUser :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: LockUser :exec
+-- LockUser serializes the requests of a user checking a per-user limit
+-- before an insert, until the end of the transaction.
+SELECT id FROM users WHERE id = $1 FOR UPDATE;
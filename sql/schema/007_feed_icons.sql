@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE feed_icons (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    source_url TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    data BYTEA NOT NULL,
+    etag TEXT NOT NULL
+);
+
+-- Feeds whose icon candidates all failed, not looked for again before retry_at.
+CREATE TABLE feed_icon_failures (
+    feed_id UUID PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    failures INTEGER NOT NULL,
+    retry_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE feed_icon_failures;
+DROP TABLE feed_icons;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN enclosure_url TEXT;
+ALTER TABLE posts ADD COLUMN enclosure_type TEXT;
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN enclosure_type;
+ALTER TABLE posts DROP COLUMN enclosure_url;
@@ -0,0 +1,179 @@
+-- +goose Up
+-- canonicalize_url mirrors urls.Canonicalize, to backfill the existing rows
+-- with the same canonical form as the ones inserted by the application:
+-- the path and the query are decoded then encoded again the way net/url
+-- does, so that spellings differing only by their escapes meet.
+-- +goose StatementBegin
+CREATE FUNCTION url_unescape(component TEXT, plus_is_space BOOLEAN) RETURNS BYTEA AS $$
+DECLARE
+    decoded BYTEA := '';
+    i INTEGER := 1;
+    c TEXT;
+BEGIN
+    WHILE i <= length(component) LOOP
+        c := substr(component, i, 1);
+        IF c = '%' THEN
+            -- An invalid escape makes net/url reject the component.
+            IF substr(component, i + 1, 2) !~ '^[0-9A-Fa-f]{2}$' THEN
+                RETURN NULL;
+            END IF;
+            decoded := decoded || decode(substr(component, i + 1, 2), 'hex');
+            i := i + 3;
+        ELSE
+            IF c = '+' AND plus_is_space THEN
+                c := ' ';
+            END IF;
+            decoded := decoded || convert_to(c, 'UTF8');
+            i := i + 1;
+        END IF;
+    END LOOP;
+    RETURN decoded;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION url_escape(component BYTEA, safe TEXT, space_is_plus BOOLEAN) RETURNS TEXT AS $$
+DECLARE
+    escaped TEXT := '';
+    b INTEGER;
+BEGIN
+    FOR i IN 0 .. length(component) - 1 LOOP
+        b := get_byte(component, i);
+        IF b BETWEEN 48 AND 57 OR b BETWEEN 65 AND 90 OR b BETWEEN 97 AND 122
+            OR (b BETWEEN 33 AND 126 AND strpos(safe, chr(b)) > 0) THEN
+            escaped := escaped || chr(b);
+        ELSIF b = 32 AND space_is_plus THEN
+            escaped := escaped || '+';
+        ELSE
+            escaped := escaped || '%' || upper(lpad(to_hex(b), 2, '0'));
+        END IF;
+    END LOOP;
+    RETURN escaped;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION canonicalize_url(raw TEXT) RETURNS TEXT AS $$
+DECLARE
+    parts TEXT[];
+    scheme TEXT;
+    authority TEXT;
+    host TEXT;
+    port TEXT;
+    raw_segment TEXT;
+    segment BYTEA;
+    segments TEXT[] := '{}';
+    canonical_query TEXT;
+BEGIN
+    parts := regexp_match(
+        regexp_replace(raw, '^\s+|\s+$', '', 'g'),
+        '^([A-Za-z][A-Za-z0-9+.-]*)://([^/?#]*)([^?#]*)(?:\?([^#]*))?'
+    );
+    IF parts IS NULL OR lower(parts[1]) NOT IN ('http', 'https') THEN
+        RETURN raw;
+    END IF;
+    scheme := lower(parts[1]);
+    authority := regexp_replace(parts[2], '^.*@', '');
+    IF authority LIKE '[%' THEN
+        host := lower(substring(authority from '^(\[[^\]]*\])'));
+        port := substring(authority from '^\[[^\]]*\]:([0-9]*)$');
+    ELSE
+        host := rtrim(lower(split_part(authority, ':', 1)), '.');
+        port := split_part(authority, ':', 2);
+    END IF;
+    IF coalesce(host, '') IN ('', '[]') THEN
+        RETURN raw;
+    END IF;
+    IF coalesce(port, '') <> '' AND NOT ((scheme = 'http' AND port = '80') OR (scheme = 'https' AND port = '443')) THEN
+        host := host || ':' || port;
+    END IF;
+    -- An escaped slash separates segments once decoded, as in net/url.
+    FOREACH raw_segment IN ARRAY string_to_array(regexp_replace(parts[3], '%2[Ff]', '/', 'g'), '/') LOOP
+        segment := url_unescape(raw_segment, false);
+        IF segment IS NULL THEN
+            RETURN raw;
+        END IF;
+        IF segment = '\x2e2e'::bytea THEN
+            IF coalesce(array_length(segments, 1), 0) > 0 THEN
+                segments := segments[1:array_length(segments, 1) - 1];
+            END IF;
+        ELSIF segment <> ''::bytea AND segment <> '\x2e'::bytea THEN
+            segments := segments || url_escape(segment, '-_.~$&+,/:;=@', false);
+        END IF;
+    END LOOP;
+    -- Pairs with a semicolon or an invalid escape are dropped, the others are
+    -- sorted by decoded key, keeping the order of the values of a key.
+    SELECT string_agg(url_escape(key, '-_.~', true) || '=' || url_escape(value, '-_.~', true), '&' ORDER BY key, ordinal)
+    INTO canonical_query
+    FROM (
+        SELECT
+            url_unescape(split_part(pair, '=', 1), true) AS key,
+            url_unescape(substr(pair, strpos(pair || '=', '=') + 1), true) AS value,
+            ordinal
+        FROM unnest(string_to_array(parts[4], '&')) WITH ORDINALITY AS q(pair, ordinal)
+        WHERE pair <> '' AND strpos(pair, ';') = 0
+    ) pairs
+    WHERE key IS NOT NULL
+        AND value IS NOT NULL
+        AND lower(url_escape(key, '-_.~', true)) NOT LIKE 'utm\_%'
+        AND lower(url_escape(key, '-_.~', true)) NOT IN (
+            'fbclid', 'gclid', 'dclid', 'msclkid', 'mc_cid', 'mc_eid', 'igshid', 'yclid',
+            '_hsenc', '_hsmi', 'ref_src', 'ref_url'
+        );
+    RETURN 'https://' || host
+        || coalesce('/' || nullif(array_to_string(segments, '/'), ''), '')
+        || coalesce('?' || canonical_query, '');
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+-- +goose StatementEnd
+
+ALTER TABLE feeds ADD COLUMN canonical_url TEXT;
+UPDATE feeds SET canonical_url = canonicalize_url(url);
+
+-- Feeds sharing a canonical URL are merged into the oldest one: its
+-- followers keep a single follow and the posts of the others are moved to it.
+CREATE TEMPORARY TABLE duplicate_feeds AS
+SELECT id, first_value(id) OVER (PARTITION BY canonical_url ORDER BY created_at, id) AS kept_id
+FROM feeds;
+DELETE FROM duplicate_feeds WHERE id = kept_id;
+
+DELETE FROM feed_follows
+WHERE id IN (
+    SELECT id FROM (
+        SELECT ff.id, row_number() OVER (PARTITION BY ff.user_id, f.canonical_url ORDER BY f.created_at, f.id) AS n
+        FROM feed_follows ff
+        JOIN feeds f ON f.id = ff.feed_id
+    ) follows
+    WHERE n > 1
+);
+UPDATE feed_follows ff SET feed_id = d.kept_id FROM duplicate_feeds d WHERE ff.feed_id = d.id;
+UPDATE posts p SET feed_id = d.kept_id FROM duplicate_feeds d WHERE p.feed_id = d.id;
+DELETE FROM feeds f USING duplicate_feeds d WHERE f.id = d.id;
+DROP TABLE duplicate_feeds;
+
+ALTER TABLE feeds ALTER COLUMN canonical_url SET NOT NULL;
+ALTER TABLE feeds ADD CONSTRAINT feeds_canonical_url_key UNIQUE (canonical_url);
+
+-- Posts sharing a canonical URL keep the oldest one.
+ALTER TABLE posts ADD COLUMN canonical_url TEXT;
+UPDATE posts SET canonical_url = canonicalize_url(url);
+DELETE FROM posts
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (PARTITION BY canonical_url ORDER BY created_at, id) AS n
+        FROM posts
+    ) duplicates
+    WHERE n > 1
+);
+ALTER TABLE posts ALTER COLUMN canonical_url SET NOT NULL;
+ALTER TABLE posts ADD CONSTRAINT posts_canonical_url_key UNIQUE (canonical_url);
+
+DROP FUNCTION canonicalize_url(TEXT);
+DROP FUNCTION url_escape(BYTEA, TEXT, BOOLEAN);
+DROP FUNCTION url_unescape(TEXT, BOOLEAN);
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN canonical_url;
+ALTER TABLE feeds DROP COLUMN canonical_url;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN truncated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE feeds ADD COLUMN last_warning TEXT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN last_warning;
+ALTER TABLE posts DROP COLUMN truncated;
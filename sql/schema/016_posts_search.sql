@@ -0,0 +1,19 @@
+-- +goose Up
+-- posts_search_vector computes the search document of a post. Searches use
+-- it through an expression index rather than a stored generated column: a
+-- column would be part of every posts row the queries return with p.*, and
+-- of the Post model with it, while the index gives the same lookups without
+-- storing the document in the table. The planner only uses the index when
+-- a query calls the function on the same columns, so the search queries
+-- write the expression exactly as it is indexed here.
+CREATE FUNCTION posts_search_vector(title TEXT, description TEXT, content TEXT) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english'::regconfig, coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('english'::regconfig, coalesce(content, '')), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (posts_search_vector(title, description, content));
+
+-- +goose Down
+DROP INDEX posts_search_vector_idx;
+DROP FUNCTION posts_search_vector(TEXT, TEXT, TEXT);
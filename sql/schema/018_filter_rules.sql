@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE filter_rules (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    field TEXT NOT NULL,
+    match_type TEXT NOT NULL,
+    pattern TEXT NOT NULL,
+    actions TEXT[] NOT NULL,
+    tag TEXT
+);
+
+CREATE INDEX filter_rules_user_id_idx ON filter_rules (user_id);
+
+-- Runs of a rule on the posts already stored, processed in the background.
+CREATE TABLE filter_rule_runs (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    filter_rule_id UUID NOT NULL REFERENCES filter_rules(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    matched BIGINT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX filter_rule_runs_status_idx ON filter_rule_runs (status, created_at);
+
+-- +goose Down
+DROP TABLE filter_rule_runs;
+DROP TABLE filter_rules;
@@ -0,0 +1,52 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN author TEXT;
+
+CREATE TABLE mutes (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (user_id, kind, value)
+);
+
+CREATE TABLE post_hides (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    hidden_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- post_visible_to_user is the single definition of the posts a user hid or
+-- muted, shared by every query listing posts in a timeline. A word mute only
+-- matches whole words: the value is escaped and must be surrounded by
+-- characters other than letters, digits or underscores.
+-- +goose StatementBegin
+CREATE FUNCTION post_visible_to_user(p posts, viewer_id UUID) RETURNS BOOLEAN AS $$
+    SELECT NOT EXISTS (
+        SELECT 1 FROM post_hides ph WHERE ph.post_id = p.id AND ph.user_id = viewer_id
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM mutes m
+        WHERE m.user_id = viewer_id
+            AND (
+                (
+                    m.kind = 'word'
+                    AND lower(concat_ws(' ', p.title, p.description))
+                        ~ ('(^|[^[:alnum:]_])' || regexp_replace(m.value, '([^[:alnum:][:space:]])', '\\\1', 'g') || '($|[^[:alnum:]_])')
+                )
+                OR (
+                    m.kind = 'domain'
+                    AND right('.' || substring(p.canonical_url from '^https?://([^/:]+)'), length(m.value) + 1) = '.' || m.value
+                )
+                OR (m.kind = 'author' AND lower(p.author) = m.value)
+            )
+    );
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION post_visible_to_user(posts, UUID);
+DROP TABLE post_hides;
+DROP TABLE mutes;
+ALTER TABLE posts DROP COLUMN author;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feed_follows
+    ADD COLUMN custom_title TEXT,
+    ADD COLUMN notes TEXT,
+    ADD COLUMN priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN hide_from_timeline BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE feed_follows
+    DROP COLUMN custom_title,
+    DROP COLUMN notes,
+    DROP COLUMN priority,
+    DROP COLUMN hide_from_timeline;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE opml_imports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    total INTEGER NOT NULL,
+    error TEXT
+);
+
+CREATE TABLE opml_import_results (
+    id UUID PRIMARY KEY,
+    import_id UUID NOT NULL REFERENCES opml_imports(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    category TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    UNIQUE (import_id, position)
+);
+
+CREATE INDEX opml_imports_status_idx ON opml_imports (status, created_at);
+
+-- +goose Down
+DROP TABLE opml_import_results;
+DROP TABLE opml_imports;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE feed_tokens (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    tag TEXT,
+    token VARCHAR(64) UNIQUE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE feed_tokens;
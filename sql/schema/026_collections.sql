@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE collections (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT
+);
+
+CREATE TABLE collection_posts (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, post_id)
+);
+
+CREATE INDEX collection_posts_added_at_idx ON collection_posts (collection_id, added_at DESC);
+
+-- +goose Down
+DROP TABLE collection_posts;
+DROP TABLE collections;